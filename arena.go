@@ -0,0 +1,108 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+// MapArena is memory allocator of Map nodes. It bump-allocates nodes,
+// fingers and spans from large slabs of memory. Free is no-op, the memory is
+// released all at once when both map and arena are not reachable anymore.
+// The arena reduces number of allocations (and objects scanned by GC) for
+// large maps, it is not suitable for maps with high churn of keys.
+//
+//	kv := skiplist.NewMap(
+//		skiplist.MapWithAllocator[K, V](skiplist.NewMapArena[K, V](4096)),
+//	)
+type MapArena[K any, V any] struct {
+	nodes   slab[Pair[K, V]]
+	fingers slab[*Pair[K, V]]
+	spans   slab[int]
+}
+
+var _ NodeAllocator[int, Pair[int, int]] = (*MapArena[int, int])(nil)
+
+// NewMapArena creates arena, each slab holds size nodes
+func NewMapArena[K any, V any](size int) *MapArena[K, V] {
+	if size < 1 {
+		size = 1
+	}
+
+	return &MapArena[K, V]{
+		nodes:   slab[Pair[K, V]]{size: size},
+		fingers: slab[*Pair[K, V]]{size: 2 * size},
+		spans:   slab[int]{size: 2 * size},
+	}
+}
+
+func (arena *MapArena[K, V]) Alloc(key K) *Pair[K, V] {
+	return arena.AllocNode(key, L)
+}
+
+func (arena *MapArena[K, V]) Free(K) {}
+
+func (arena *MapArena[K, V]) AllocNode(key K, level int) *Pair[K, V] {
+	node := &arena.nodes.alloc(1)[0]
+	node.Fingers = arena.fingers.alloc(level)
+	node.spans = arena.spans.alloc(level)
+	return node
+}
+
+func (arena *MapArena[K, V]) FreeNode(*Pair[K, V]) {}
+
+// SetArena is memory allocator of Set nodes, see MapArena for details.
+type SetArena[K any] struct {
+	nodes   slab[Element[K]]
+	fingers slab[*Element[K]]
+	spans   slab[int]
+}
+
+var _ NodeAllocator[int, Element[int]] = (*SetArena[int])(nil)
+
+// NewSetArena creates arena, each slab holds size nodes
+func NewSetArena[K any](size int) *SetArena[K] {
+	if size < 1 {
+		size = 1
+	}
+
+	return &SetArena[K]{
+		nodes:   slab[Element[K]]{size: size},
+		fingers: slab[*Element[K]]{size: 2 * size},
+		spans:   slab[int]{size: 2 * size},
+	}
+}
+
+func (arena *SetArena[K]) Alloc(key K) *Element[K] {
+	return arena.AllocNode(key, L)
+}
+
+func (arena *SetArena[K]) Free(K) {}
+
+func (arena *SetArena[K]) AllocNode(key K, level int) *Element[K] {
+	node := &arena.nodes.alloc(1)[0]
+	node.Fingers = arena.fingers.alloc(level)
+	node.spans = arena.spans.alloc(level)
+	return node
+}
+
+func (arena *SetArena[K]) FreeNode(*Element[K]) {}
+
+// slab of memory, the expected number of fingers per node is 1/(1-p) < 2,
+// therefore fingers and spans slabs are twice as large as nodes slab.
+type slab[T any] struct {
+	size int
+	buf  []T
+}
+
+func (s *slab[T]) alloc(n int) []T {
+	if len(s.buf) < n {
+		s.buf = make([]T, max(s.size, n))
+	}
+
+	x := s.buf[:n:n]
+	s.buf = s.buf[n:]
+	return x
+}
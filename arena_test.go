@@ -0,0 +1,102 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestMapArena(t *testing.T) {
+	kv := skiplist.NewMap(
+		skiplist.MapWithAllocator[int, int](skiplist.NewMapArena[int, int](64)),
+	)
+	for i := 0; i < 10000; i++ {
+		kv.Put(i, i)
+	}
+	for i := 0; i < 10000; i += 2 {
+		kv.Cut(i)
+	}
+
+	n := 0
+	for k, v := range kv.All() {
+		it.Then(t).Should(
+			it.Equal(k, 2*n+1),
+			it.Equal(v, k),
+		)
+		n++
+	}
+
+	it.Then(t).Should(
+		it.Equal(n, 5000),
+		it.Equal(kv.Length(), 5000),
+		it.Equal(kv.At(100).Key, 201),
+	)
+
+	kv.Clear()
+	kv.Put(1, 1)
+	it.Then(t).Should(it.Equal(kv.Length(), 1))
+}
+
+func TestSetArena(t *testing.T) {
+	set := skiplist.NewSet(
+		skiplist.SetWithAllocator[int](skiplist.NewSetArena[int](64)),
+	)
+	for i := 10000; i > 0; i-- {
+		set.Add(i)
+	}
+	set.CutRange(100, 200)
+
+	has := set.Has(150)
+	it.Then(t).Should(
+		it.Equal(set.Length(), 9900),
+		it.Equal(set.At(99).Key, 200),
+		it.True(!has),
+	)
+}
+
+// poison allocator zeroes freed nodes, it detects use after free
+type poisonMap struct{ *skiplist.MapArena[int, int] }
+
+func (poisonMap) FreeNode(el *skiplist.Pair[int, int]) { *el = skiplist.Pair[int, int]{} }
+
+type poisonSet struct{ *skiplist.SetArena[int] }
+
+func (poisonSet) FreeNode(el *skiplist.Element[int]) { *el = skiplist.Element[int]{} }
+
+func TestArenaFreeNode(t *testing.T) {
+	kv := skiplist.NewMap(
+		skiplist.MapWithAllocator[int, int](poisonMap{skiplist.NewMapArena[int, int](64)}),
+	)
+	set := skiplist.NewSet(
+		skiplist.SetWithAllocator[int](poisonSet{skiplist.NewSetArena[int](64)}),
+	)
+	for i := 0; i < 1000; i++ {
+		kv.Put(i, i)
+		set.Add(i)
+	}
+
+	cutKV := kv.CutRange(100, 200)
+	cutSet := set.CutRange(100, 200)
+	it.Then(t).Should(
+		it.Equal(cutKV, 100),
+		it.Equal(cutSet, 100),
+		it.Equal(kv.At(100).Key, 200),
+		it.Equal(set.At(100).Key, 200),
+	)
+
+	kv.Clear()
+	set.Clear()
+	it.Then(t).Should(
+		it.Equal(kv.Length(), 0),
+		it.Equal(set.Length(), 0),
+	)
+}
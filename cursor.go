@@ -0,0 +1,250 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/fogfish/golem/trait/pair"
+	"github.com/fogfish/golem/trait/seq"
+)
+
+// ErrInvalidCursor is returned if signature of the cursor token is not valid
+var ErrInvalidCursor = errors.New("skiplist: invalid cursor")
+
+// Cursor captures position and direction of iterator so that iteration is
+// resumed later, e.g. by the following page of REST api. Cursor is encoded
+// into token that is opaque for clients but not tamper-proof, anyone can
+// decode it and forge keys. Use Sign and DecodeSignedCursor if the token
+// is exposed to untrusted clients.
+//
+//	c := skiplist.NewCursor[string](20)
+//	seq := kv.Resume(c)
+//	for has := seq != nil; has; has = seq.Next() {
+//		c = c.Next(seq.Key())
+//	}
+//	token, err := c.Sign(secret)
+type Cursor[K any] struct {
+	// the last visited key, iteration is resumed after it.
+	After *K `json:"a,omitempty"`
+
+	// the number of keys visited by resumed iterator, 0 means no limit.
+	Limit int `json:"n,omitempty"`
+
+	// iteration in descending order of keys
+	Desc bool `json:"d,omitempty"`
+}
+
+// NewCursor creates cursor at the beginning of container
+func NewCursor[K any](limit int) Cursor[K] {
+	return Cursor[K]{Limit: limit}
+}
+
+// NewDescendingCursor creates cursor at the end of container, the iteration
+// is resumed in descending order of keys.
+func NewDescendingCursor[K any](limit int) Cursor[K] {
+	return Cursor[K]{Limit: limit, Desc: true}
+}
+
+// Next moves cursor after the key
+func (c Cursor[K]) Next(key K) Cursor[K] {
+	return Cursor[K]{After: &key, Limit: c.Limit, Desc: c.Desc}
+}
+
+// Encode cursor into opaque token
+func (c Cursor[K]) Encode() (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign encodes cursor into token authenticated by HMAC-SHA256 with the secret
+func (c Cursor[K]) Sign(secret []byte) (string, error) {
+	token, err := c.Encode()
+	if err != nil {
+		return "", err
+	}
+
+	return token + "." + base64.RawURLEncoding.EncodeToString(cursorMAC(token, secret)), nil
+}
+
+// DecodeCursor decodes cursor from opaque token
+func DecodeCursor[K any](token string) (Cursor[K], error) {
+	var c Cursor[K]
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, err
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// DecodeSignedCursor decodes cursor from token produced by Sign, it returns
+// ErrInvalidCursor if the token is not signed by the secret.
+func DecodeSignedCursor[K any](token string, secret []byte) (Cursor[K], error) {
+	data, sign, ok := strings.Cut(token, ".")
+	if !ok {
+		return Cursor[K]{}, ErrInvalidCursor
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(sign)
+	if err != nil || !hmac.Equal(mac, cursorMAC(data, secret)) {
+		return Cursor[K]{}, ErrInvalidCursor
+	}
+
+	return DecodeCursor[K](data)
+}
+
+func cursorMAC(token string, secret []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(token))
+	return h.Sum(nil)
+}
+
+// Resume iteration over map at the cursor
+func (kv *Map[K, V]) Resume(c Cursor[K]) pair.Seq[K, V] {
+	if c.Desc {
+		at := kv.length - 1
+		if c.After != nil {
+			at, _ = kv.RankOf(*c.After)
+			at--
+		}
+
+		if at < 0 {
+			return nil
+		}
+
+		return &descMap[K, V]{kv: kv, at: at, n: c.Limit}
+	}
+
+	el := kv.Values()
+	if c.After != nil {
+		el = kv.Successor(*c.After)
+		if el != nil && kv.compare(el.Key, *c.After) == 0 {
+			el = el.Next()
+		}
+	}
+
+	seq := ForMap(kv, el)
+	if seq == nil {
+		return nil
+	}
+
+	if c.Limit > 0 {
+		return Take[K, V](seq, c.Limit)
+	}
+
+	return seq
+}
+
+// Resume iteration over set at the cursor
+func (set *Set[K]) Resume(c Cursor[K]) seq.Seq[K] {
+	if c.Desc {
+		at := set.length - 1
+		if c.After != nil {
+			at, _ = set.RankOf(*c.After)
+			at--
+		}
+
+		if at < 0 {
+			return nil
+		}
+
+		return &descSet[K]{set: set, at: at, n: c.Limit}
+	}
+
+	el := set.Values()
+	if c.After != nil {
+		el = set.Successor(*c.After)
+		if el != nil && set.compare(el.Key, *c.After) == 0 {
+			el = el.Next()
+		}
+	}
+
+	seq := ForSet(set, el)
+	if seq == nil {
+		return nil
+	}
+
+	if c.Limit > 0 {
+		return TakeKeys[K](seq, c.Limit)
+	}
+
+	return seq
+}
+
+// descending iterator over map, it walks ranks of pairs, each step is
+// O(log n). Zero n means no limit.
+type descMap[K, V any] struct {
+	kv *Map[K, V]
+	el *Pair[K, V]
+	at int
+	n  int
+}
+
+func (it *descMap[K, V]) pair() *Pair[K, V] {
+	if it.el == nil {
+		it.el = it.kv.At(it.at)
+	}
+	return it.el
+}
+
+func (it *descMap[K, V]) Key() K   { return it.pair().Key }
+func (it *descMap[K, V]) Value() V { return it.pair().Value }
+func (it *descMap[K, V]) Next() bool {
+	if it.n > 0 {
+		if it.n--; it.n == 0 {
+			return false
+		}
+	}
+
+	if it.at <= 0 {
+		return false
+	}
+
+	it.at--
+	it.el = nil
+	return true
+}
+
+// descending iterator over set, see descMap
+type descSet[K any] struct {
+	set *Set[K]
+	at  int
+	n   int
+}
+
+func (it *descSet[K]) Value() K { return it.set.At(it.at).Key }
+func (it *descSet[K]) Next() bool {
+	if it.n > 0 {
+		if it.n--; it.n == 0 {
+			return false
+		}
+	}
+
+	if it.at <= 0 {
+		return false
+	}
+
+	it.at--
+	return true
+}
@@ -0,0 +1,128 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestCursor(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	set := skiplist.NewSet[int]()
+	for i := 0; i < 25; i++ {
+		kv.Put(i*2, i)
+		set.Add(i * 2)
+	}
+
+	t.Run("Map", func(t *testing.T) {
+		keys := []int{}
+		pages := 0
+
+		c := skiplist.NewCursor[int](10)
+		for {
+			token, err := c.Encode()
+			it.Then(t).Should(it.Nil(err))
+
+			c, err = skiplist.DecodeCursor[int](token)
+			it.Then(t).Should(it.Nil(err))
+
+			seq := kv.Resume(c)
+			if seq == nil {
+				break
+			}
+
+			pages++
+			for has := true; has; has = seq.Next() {
+				keys = append(keys, seq.Key())
+				c = c.Next(seq.Key())
+			}
+		}
+
+		it.Then(t).Should(
+			it.Equal(pages, 3),
+			it.Equal(len(keys), 25),
+			it.Equal(keys[24], 48),
+		)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		seq := set.Resume(skiplist.NewCursor[int](3).Next(11))
+		it.Then(t).Should(
+			it.Seq(valuesOf(seq)).Equal(12, 14, 16),
+		)
+
+		seq = set.Resume(skiplist.NewCursor[int](0).Next(44))
+		it.Then(t).Should(
+			it.Seq(valuesOf(seq)).Equal(46, 48),
+		)
+	})
+
+	t.Run("Descending", func(t *testing.T) {
+		keys := []int{}
+		pages := 0
+
+		c := skiplist.NewDescendingCursor[int](10)
+		for seq := kv.Resume(c); seq != nil; seq = kv.Resume(c) {
+			token, err := c.Encode()
+			it.Then(t).Should(it.Nil(err))
+
+			c, err = skiplist.DecodeCursor[int](token)
+			it.Then(t).Should(it.Nil(err))
+
+			pages++
+			for has := true; has; has = seq.Next() {
+				keys = append(keys, seq.Key())
+				c = c.Next(seq.Key())
+			}
+		}
+
+		it.Then(t).Should(
+			it.Equal(pages, 3),
+			it.Equal(len(keys), 25),
+			it.Equal(keys[0], 48),
+			it.Equal(keys[24], 0),
+		)
+
+		seq := set.Resume(skiplist.NewDescendingCursor[int](3).Next(11))
+		it.Then(t).Should(
+			it.Seq(valuesOf(seq)).Equal(10, 8, 6),
+		)
+	})
+
+	t.Run("Signed", func(t *testing.T) {
+		secret := []byte("secret")
+		token, err := skiplist.NewCursor[int](10).Next(20).Sign(secret)
+		it.Then(t).Should(it.Nil(err))
+
+		c, err := skiplist.DecodeSignedCursor[int](token, secret)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(*c.After, 20),
+			it.Equal(c.Limit, 10),
+		)
+
+		forged, _ := skiplist.NewCursor[int](10).Next(40).Encode()
+		_, err = skiplist.DecodeSignedCursor[int](forged+token[len(forged):], secret)
+		it.Then(t).Should(it.Equal(err, skiplist.ErrInvalidCursor))
+
+		_, err = skiplist.DecodeSignedCursor[int](token, []byte("other"))
+		it.Then(t).Should(it.Equal(err, skiplist.ErrInvalidCursor))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := skiplist.DecodeCursor[int]("!")
+		it.Then(t).ShouldNot(it.Nil(err))
+
+		_, err = skiplist.DecodeCursor[int]("e30x")
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+}
@@ -0,0 +1,1123 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"unsafe"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// ErrUnknownOrder is returned when container is decoded into zero value but
+// the ordering of keys is unknown. Create container with constructor
+// (e.g. NewMapWith) before decoding.
+var ErrUnknownOrder = errors.New("skiplist: ordering of keys is unknown")
+
+var (
+	errMalformed = errors.New("skiplist: malformed encoding")
+	errNotSorted = errors.New("skiplist: keys are not sorted")
+)
+
+const (
+	// maxFieldSize is the largest length-prefixed field accepted by decoders,
+	// longer fields are treated as malformed input.
+	maxFieldSize = 1 << 30
+
+	// readChunkSize bounds allocation ahead of bytes actually read
+	readChunkSize = 64 << 10
+)
+
+// natural ordering of types with built-in underlying type (e.g. type ID
+// uint64), nil if the type is not supported
+func natural[K any]() func(K, K) int {
+	switch reflect.TypeFor[K]().Kind() {
+	case reflect.String:
+		return underlying[K](ord.String.Compare)
+	case reflect.Int:
+		return underlying[K](ord.Int.Compare)
+	case reflect.Int8:
+		return underlying[K](ord.Int8.Compare)
+	case reflect.Int16:
+		return underlying[K](ord.Int16.Compare)
+	case reflect.Int32:
+		return underlying[K](ord.Int32.Compare)
+	case reflect.Int64:
+		return underlying[K](ord.Int64.Compare)
+	case reflect.Uint:
+		return underlying[K](ord.Uint.Compare)
+	case reflect.Uint8:
+		return underlying[K](ord.Uint8.Compare)
+	case reflect.Uint16:
+		return underlying[K](ord.Uint16.Compare)
+	case reflect.Uint32:
+		return underlying[K](ord.Uint32.Compare)
+	case reflect.Uint64:
+		return underlying[K](ord.Uint64.Compare)
+	case reflect.Float32:
+		return underlying[K](ord.Float32.Compare)
+	case reflect.Float64:
+		return underlying[K](ord.Float64.Compare)
+	default:
+		return nil
+	}
+}
+
+// underlying lifts comparator of built-in type T to type K of the same kind,
+// named type shares memory layout with its underlying type.
+func underlying[K any, T any](cmp func(T, T) int) func(K, K) int {
+	if f, ok := any(cmp).(func(K, K) int); ok {
+		return f
+	}
+
+	return func(a, b K) int {
+		return cmp(*(*T)(unsafe.Pointer(&a)), *(*T)(unsafe.Pointer(&b)))
+	}
+}
+
+// initialize zero value of the map before decoding
+func (kv *Map[K, V]) init() error {
+	if kv.head != nil {
+		return nil
+	}
+
+	cmp := natural[K]()
+	if cmp == nil {
+		return ErrUnknownOrder
+	}
+
+	*kv = *NewMapWith[K, V](ord.From[K](cmp))
+	return nil
+}
+
+// initialize zero value of the set before decoding
+func (set *Set[K]) init() error {
+	if set.head != nil {
+		return nil
+	}
+
+	cmp := natural[K]()
+	if cmp == nil {
+		return ErrUnknownOrder
+	}
+
+	*set = *NewSetWith[K](ord.From[K](cmp))
+	return nil
+}
+
+// initialize zero value of the map before decoding
+func (kv *HashMap[K, V]) init() error {
+	if kv.keys != nil {
+		return nil
+	}
+
+	cmp := natural[K]()
+	if cmp == nil {
+		return ErrUnknownOrder
+	}
+
+	*kv = *NewHashMapWith[K, V](ord.From[K](cmp))
+	kv.exact = true
+	return nil
+}
+
+//------------------------------------------------------------------------------
+//
+// encoding/gob
+//
+//------------------------------------------------------------------------------
+
+type gobMap[K, V any] struct {
+	Keys   []K
+	Values []V
+}
+
+// GobEncode implements gob.GobEncoder, pairs are encoded in key order.
+// Values are encoded with codec if the map is configured with one.
+func (kv *Map[K, V]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if kv.codec == nil {
+		err := gob.NewEncoder(&buf).Encode(gobMap[K, V]{
+			Keys:   kv.KeysSlice(),
+			Values: kv.ValuesSlice(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	seq := gobMap[K, []byte]{Keys: kv.KeysSlice(), Values: make([][]byte, 0, kv.length)}
+	for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		b, err := kv.codec.Encode(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		seq.Values = append(seq.Values, b)
+	}
+
+	if err := gob.NewEncoder(&buf).Encode(seq); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, it replaces content of the map.
+// The configuration of the map is retained.
+func (kv *Map[K, V]) GobDecode(b []byte) error {
+	if err := kv.init(); err != nil {
+		return err
+	}
+
+	seq, err := kv.gobDecode(b)
+	if err != nil {
+		return err
+	}
+
+	if len(seq.Keys) != len(seq.Values) {
+		return errMalformed
+	}
+
+	pairs := make([]Pair[K, V], len(seq.Keys))
+	for i := range seq.Keys {
+		pairs[i].Key, pairs[i].Value = seq.Keys[i], seq.Values[i]
+	}
+
+	kv.Clear()
+	kv.PutAll(pairs...)
+
+	return nil
+}
+
+func (kv *Map[K, V]) gobDecode(b []byte) (gobMap[K, V], error) {
+	if kv.codec == nil {
+		var seq gobMap[K, V]
+		err := gob.NewDecoder(bytes.NewReader(b)).Decode(&seq)
+		return seq, err
+	}
+
+	var raw gobMap[K, []byte]
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&raw); err != nil {
+		return gobMap[K, V]{}, err
+	}
+
+	seq := gobMap[K, V]{Keys: raw.Keys, Values: make([]V, len(raw.Values))}
+	for i, x := range raw.Values {
+		val, err := kv.codec.Decode(x)
+		if err != nil {
+			return gobMap[K, V]{}, err
+		}
+		seq.Values[i] = val
+	}
+
+	return seq, nil
+}
+
+// GobEncode implements gob.GobEncoder, keys are encoded in order
+func (set *Set[K]) GobEncode() ([]byte, error) {
+	keys := make([]K, 0, set.length)
+	for e := set.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		keys = append(keys, e.Key)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(keys); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, it replaces content of the set.
+// The configuration of the set is retained.
+func (set *Set[K]) GobDecode(b []byte) error {
+	if err := set.init(); err != nil {
+		return err
+	}
+
+	var keys []K
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&keys); err != nil {
+		return err
+	}
+
+	set.Clear()
+	set.AddAll(keys...)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder, pairs are encoded in key order
+func (kv *HashMap[K, V]) GobEncode() ([]byte, error) {
+	seq := gobMap[K, V]{
+		Keys:   make([]K, 0, kv.Length()),
+		Values: make([]V, 0, kv.Length()),
+	}
+	for e := kv.keys.Values(); e != nil; e = e.Next() {
+		seq.Keys = append(seq.Keys, e.Key)
+		seq.Values = append(seq.Values, kv.values[e.Key])
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(seq); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, it replaces content of the map.
+// The configuration of the map is retained.
+func (kv *HashMap[K, V]) GobDecode(b []byte) error {
+	if err := kv.init(); err != nil {
+		return err
+	}
+
+	var seq gobMap[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&seq); err != nil {
+		return err
+	}
+
+	if len(seq.Keys) != len(seq.Values) {
+		return errMalformed
+	}
+
+	kv.Clear()
+	kv.keys.AddAll(seq.Keys...)
+	for i, key := range seq.Keys {
+		kv.values[key] = seq.Values[i]
+	}
+
+	return nil
+}
+
+//------------------------------------------------------------------------------
+//
+// encoding/json
+//
+//------------------------------------------------------------------------------
+
+// MarshalJSON encodes map as JSON object, members follow the order of keys.
+// Keys are encoded as strings, non-string keys are quoted.
+func (kv *Map[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		if e != kv.head.Fingers[0] {
+			buf.WriteByte(',')
+		}
+
+		key, err := marshalJSONKey(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		val, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func marshalJSONKey[K any](key K) ([]byte, error) {
+	b, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) > 0 && b[0] == '"' {
+		return b, nil
+	}
+
+	return json.Marshal(string(b))
+}
+
+func unmarshalJSONKey[K any](s string) (K, error) {
+	var key K
+
+	if err := json.Unmarshal([]byte(strconv.Quote(s)), &key); err == nil {
+		return key, nil
+	}
+
+	err := json.Unmarshal([]byte(s), &key)
+	return key, err
+}
+
+// UnmarshalJSON decodes map from JSON object, it replaces content of the map.
+// The configuration of the map is retained.
+func (kv *Map[K, V]) UnmarshalJSON(b []byte) error {
+	if err := kv.init(); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if tkn, err := dec.Token(); err != nil || tkn != json.Delim('{') {
+		return errMalformed
+	}
+
+	pairs := []Pair[K, V]{}
+	for dec.More() {
+		tkn, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, err := unmarshalJSONKey[K](tkn.(string))
+		if err != nil {
+			return err
+		}
+
+		var val V
+		if err := dec.Decode(&val); err != nil {
+			return err
+		}
+
+		pairs = append(pairs, Pair[K, V]{Key: key, Value: val})
+	}
+
+	kv.Clear()
+	kv.PutAll(pairs...)
+
+	return nil
+}
+
+// MarshalJSON encodes set as sorted JSON array
+func (set *Set[K]) MarshalJSON() ([]byte, error) {
+	keys := make([]K, 0, set.length)
+	for e := set.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		keys = append(keys, e.Key)
+	}
+
+	return json.Marshal(keys)
+}
+
+// UnmarshalJSON decodes set from JSON array, it replaces content of the set.
+// The configuration of the set is retained.
+func (set *Set[K]) UnmarshalJSON(b []byte) error {
+	if err := set.init(); err != nil {
+		return err
+	}
+
+	var keys []K
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return err
+	}
+
+	set.Clear()
+	set.AddAll(keys...)
+
+	return nil
+}
+
+//------------------------------------------------------------------------------
+//
+// binary
+//
+// The stream starts with magic bytes and number of pairs, followed by
+// length-prefixed keys and values in the key order:
+//
+//	"skl\x01" uvarint(n) { uvarint(len) key uvarint(len) value }
+//
+// Strings, byte slices, booleans and numbers (including named types) are
+// supported as keys and values, other values require Codec (see MapWithCodec).
+//
+//------------------------------------------------------------------------------
+
+var magic = [4]byte{'s', 'k', 'l', 1}
+
+// WriteTo implements io.WriterTo, it streams pairs of the map in binary format.
+func (kv *Map[K, V]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	var buf []byte
+
+	buf = append(buf, magic[:]...)
+	buf = binary.AppendUvarint(buf, uint64(kv.length))
+	if _, err := bw.Write(buf); err != nil {
+		return cw.n, err
+	}
+
+	for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		var err error
+
+		buf = buf[:0]
+		if buf, err = appendBinaryField(buf, e.Key); err != nil {
+			return cw.n, err
+		}
+		if buf, err = kv.appendValue(buf, e.Value); err != nil {
+			return cw.n, err
+		}
+		if _, err = bw.Write(buf); err != nil {
+			return cw.n, err
+		}
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ReadFrom implements io.ReaderFrom, it replaces content of the map with
+// pairs streamed in binary format. The configuration of the map is retained.
+func (kv *Map[K, V]) ReadFrom(r io.Reader) (int64, error) {
+	if err := kv.init(); err != nil {
+		return 0, err
+	}
+
+	cr := newCountReader(r)
+
+	var head [4]byte
+	if _, err := io.ReadFull(cr, head[:]); err != nil {
+		return cr.n, err
+	}
+	if head != magic {
+		return cr.n, errMalformed
+	}
+
+	n, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return cr.n, err
+	}
+
+	kv.Clear()
+	b := newMapBuilder(kv)
+
+	var buf []byte
+	for i := uint64(0); i < n; i++ {
+		var (
+			key K
+			val V
+		)
+
+		if buf, err = readBinaryField(cr, buf, &key); err != nil {
+			return cr.n, err
+		}
+		if buf, err = kv.readValue(cr, buf, &val); err != nil {
+			return cr.n, err
+		}
+
+		if !b.append(key, val) {
+			return cr.n, errNotSorted
+		}
+	}
+
+	return cr.n, nil
+}
+
+func (kv *Map[K, V]) appendValue(buf []byte, val V) ([]byte, error) {
+	if kv.codec == nil {
+		return appendBinaryField(buf, val)
+	}
+
+	b, err := kv.codec.Encode(val)
+	if err != nil {
+		return buf, err
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...), nil
+}
+
+func (kv *Map[K, V]) readValue(r *countReader, buf []byte, val *V) ([]byte, error) {
+	if kv.codec == nil {
+		return readBinaryField(r, buf, val)
+	}
+
+	var b []byte
+	buf, err := readBinaryField(r, buf, &b)
+	if err != nil {
+		return buf, err
+	}
+
+	*val, err = kv.codec.Decode(b)
+	return buf, err
+}
+
+// ReadMapFrom builds instance of map from pairs streamed in binary format.
+func ReadMapFrom[K Key, V any](r io.Reader, opts ...MapConfig[K, V]) (*Map[K, V], error) {
+	kv := NewMap(opts...)
+	if _, err := kv.ReadFrom(r); err != nil {
+		return nil, err
+	}
+
+	return kv, nil
+}
+
+// LoadSorted builds map from the stream of sorted records. Each record is
+// length-prefixed (uvarint) bytes, decode transforms the record into pair.
+// The record buffer is reused, decode must not retain it. Pairs are spliced
+// at the tail of the map, the stream is never held in memory.
+func LoadSorted[K Key, V any](r io.Reader, decode func([]byte) (K, V, error), opts ...MapConfig[K, V]) (*Map[K, V], error) {
+	kv := NewMap(opts...)
+	b := newMapBuilder(kv)
+	cr := newCountReader(r)
+
+	var buf []byte
+	for {
+		size, err := binary.ReadUvarint(cr)
+		if err == io.EOF {
+			return kv, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if size > maxFieldSize {
+			return nil, errMalformed
+		}
+
+		if buf, err = readBytes(cr, buf, int(size)); err != nil {
+			return nil, err
+		}
+
+		key, val, err := decode(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		if !b.append(key, val) {
+			return nil, errNotSorted
+		}
+	}
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+type countReader struct {
+	r byteReader
+	n int64
+}
+
+func newCountReader(r io.Reader) *countReader {
+	if cr, ok := r.(*countReader); ok {
+		return cr
+	}
+
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	return &countReader{r: br}
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *countReader) ReadByte() (byte, error) {
+	c, err := r.r.ReadByte()
+	if err == nil {
+		r.n++
+	}
+	return c, err
+}
+
+func appendBinaryField[T any](buf []byte, x T) ([]byte, error) {
+	b, err := marshalBinary(x)
+	if err != nil {
+		return buf, err
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...), nil
+}
+
+func readBinaryField[T any](r *countReader, buf []byte, x *T) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return buf, err
+	}
+
+	if size > maxFieldSize {
+		return buf, errMalformed
+	}
+
+	if buf, err = readBytes(r, buf, int(size)); err != nil {
+		return buf, err
+	}
+
+	return buf, unmarshalBinary(buf, x)
+}
+
+// readBytes reads exactly size bytes into the buffer. The size comes from
+// untrusted input, the buffer grows by chunks as bytes arrive so that
+// allocation is bounded by actual length of the input.
+func readBytes(r io.Reader, buf []byte, size int) ([]byte, error) {
+	if cap(buf) >= size {
+		buf = buf[:size]
+		_, err := io.ReadFull(r, buf)
+		return buf, err
+	}
+
+	buf = buf[:0]
+	for len(buf) < size {
+		n := min(size-len(buf), readChunkSize)
+		buf = slices.Grow(buf, n)
+		if _, err := io.ReadFull(r, buf[len(buf):len(buf)+n]); err != nil {
+			return buf, err
+		}
+		buf = buf[:len(buf)+n]
+	}
+
+	return buf, nil
+}
+
+// marshal built-in types into binary format
+func marshalBinary[T any](x T) ([]byte, error) {
+	v := reflect.ValueOf(&x).Elem()
+
+	switch v.Kind() {
+	case reflect.String:
+		return []byte(v.String()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+	case reflect.Bool:
+		if v.Bool() {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(nil, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(nil, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.BigEndian.AppendUint64(nil, math.Float64bits(v.Float())), nil
+	}
+
+	return nil, fmt.Errorf("skiplist: binary encoding of %T is not supported", x)
+}
+
+// unmarshal built-in types from binary format
+func unmarshalBinary[T any](b []byte, x *T) error {
+	v := reflect.ValueOf(x).Elem()
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(string(b))
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(bytes.Clone(b))
+			return nil
+		}
+	case reflect.Bool:
+		if len(b) != 1 {
+			return errMalformed
+		}
+		v.SetBool(b[0] != 0)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, n := binary.Varint(b)
+		if n != len(b) {
+			return errMalformed
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, n := binary.Uvarint(b)
+		if n != len(b) {
+			return errMalformed
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if len(b) != 8 {
+			return errMalformed
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(b)))
+		return nil
+	}
+
+	return fmt.Errorf("skiplist: binary encoding of %T is not supported", *x)
+}
+
+//------------------------------------------------------------------------------
+//
+// Save / Load
+//
+// Snapshot of container consists of header and data. The header defines kind
+// of container, ordering of keys and the probability table:
+//
+//	"skl\x02" kind flags float64[L]
+//
+// The data of Map follows the binary format (see WriteTo), other containers
+// encode number of elements followed by length-prefixed fields.
+//
+//------------------------------------------------------------------------------
+
+var magicSnapshot = [4]byte{'s', 'k', 'l', 2}
+
+const (
+	kindMap     = 'M'
+	kindSet     = 'S'
+	kindHashMap = 'H'
+	kindGF2     = 'G'
+
+	flagDescending = 1 << 0
+)
+
+func writeHeader(w io.Writer, kind byte, desc bool, ptable *[L]float64) error {
+	buf := make([]byte, 0, 6+8*L)
+	buf = append(buf, magicSnapshot[:]...)
+
+	flags := byte(0)
+	if desc {
+		flags |= flagDescending
+	}
+	buf = append(buf, kind, flags)
+
+	for _, p := range ptable {
+		buf = binary.BigEndian.AppendUint64(buf, math.Float64bits(p))
+	}
+
+	_, err := w.Write(buf)
+	return err
+}
+
+func readHeader(r io.Reader, kind byte) (bool, [L]float64, error) {
+	var ptable [L]float64
+
+	buf := make([]byte, 6+8*L)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return false, ptable, err
+	}
+
+	if [4]byte(buf[:4]) != magicSnapshot || buf[4] != kind {
+		return false, ptable, errMalformed
+	}
+
+	for i := range ptable {
+		ptable[i] = math.Float64frombits(binary.BigEndian.Uint64(buf[6+8*i:]))
+	}
+
+	return buf[5]&flagDescending != 0, ptable, nil
+}
+
+// Save writes snapshot of the map, both data and configuration
+func (kv *Map[K, V]) Save(w io.Writer) error {
+	if err := writeHeader(w, kindMap, kv.desc, &kv.ptable); err != nil {
+		return err
+	}
+
+	_, err := kv.WriteTo(w)
+	return err
+}
+
+// Load restores the map from snapshot, it replaces both data and configuration.
+// The snapshot keeps the direction of ordering but not the comparator itself,
+// the map must be created with the type class used by the saved one (zero
+// value map uses natural ordering of keys). The map is not changed on error.
+func (kv *Map[K, V]) Load(r io.Reader) error {
+	if err := kv.init(); err != nil {
+		return err
+	}
+
+	cr := newCountReader(r)
+
+	desc, ptable, err := readHeader(cr, kindMap)
+	if err != nil {
+		return err
+	}
+
+	// snapshot is decoded into blank map of same configuration
+	blank := *kv
+	blank.head = &Pair[K, V]{Fingers: make([]*Pair[K, V], L), spans: make([]int, L)}
+	blank.length = 0
+	blank.shared = false
+	blank.finger = nil
+	blank.onPut, blank.onCut, blank.watch = nil, nil, nil
+
+	if blank.desc != desc {
+		blank.reverse()
+	}
+	blank.ptable = ptable
+
+	if _, err := blank.ReadFrom(cr); err != nil {
+		blank.Clear()
+		return err
+	}
+
+	kv.Clear()
+	kv.head, kv.length = blank.head, blank.length
+	kv.compare, kv.unprobed, kv.desc = blank.compare, blank.unprobed, blank.desc
+	kv.ptable = blank.ptable
+
+	return nil
+}
+
+// Save writes snapshot of the set, both data and configuration
+func (set *Set[K]) Save(w io.Writer) error {
+	if err := writeHeader(w, kindSet, set.desc, &set.ptable); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	buf := binary.AppendUvarint(nil, uint64(set.length))
+	for e := set.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		var err error
+		if buf, err = appendBinaryField(buf, e.Key); err != nil {
+			return err
+		}
+		if _, err = bw.Write(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Load restores the set from snapshot, it replaces both data and configuration.
+// The ordering of keys is restored from the type class of the set, see
+// Map.Load. The set is not changed on error.
+func (set *Set[K]) Load(r io.Reader) error {
+	if err := set.init(); err != nil {
+		return err
+	}
+
+	cr := newCountReader(r)
+
+	desc, ptable, err := readHeader(cr, kindSet)
+	if err != nil {
+		return err
+	}
+
+	keys, err := readKeys[K](cr)
+	if err != nil {
+		return err
+	}
+
+	set.Clear()
+	if set.desc != desc {
+		set.reverse()
+	}
+	set.ptable = ptable
+	set.AddAll(keys...)
+
+	return nil
+}
+
+func readKeys[K any](r *countReader) ([]K, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	keys := make([]K, 0, min(n, 1<<16))
+	for i := uint64(0); i < n; i++ {
+		var key K
+		if buf, err = readBinaryField(r, buf, &key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Save writes snapshot of the map, both data and configuration
+func (kv *HashMap[K, V]) Save(w io.Writer) error {
+	if err := writeHeader(w, kindHashMap, kv.keys.desc, &kv.keys.ptable); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	buf := binary.AppendUvarint(nil, uint64(kv.keys.length))
+	for e := kv.keys.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		var err error
+		if buf, err = appendBinaryField(buf, e.Key); err != nil {
+			return err
+		}
+		if buf, err = appendBinaryField(buf, kv.values[e.Key]); err != nil {
+			return err
+		}
+		if _, err = bw.Write(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Load restores the map from snapshot, it replaces both data and configuration.
+// The ordering of keys is restored from the type class of the map, see
+// Map.Load. The map is not changed on error.
+func (kv *HashMap[K, V]) Load(r io.Reader) error {
+	if err := kv.init(); err != nil {
+		return err
+	}
+
+	cr := newCountReader(r)
+
+	desc, ptable, err := readHeader(cr, kindHashMap)
+	if err != nil {
+		return err
+	}
+
+	n, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return err
+	}
+
+	var (
+		buf  []byte
+		keys = make([]K, 0, min(n, 1<<16))
+		vals = make([]V, 0, min(n, 1<<16))
+	)
+	for i := uint64(0); i < n; i++ {
+		var (
+			key K
+			val V
+		)
+
+		if buf, err = readBinaryField(cr, buf, &key); err != nil {
+			return err
+		}
+		if buf, err = readBinaryField(cr, buf, &val); err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+		vals = append(vals, val)
+	}
+
+	kv.Clear()
+	if kv.keys.desc != desc {
+		kv.keys.reverse()
+	}
+	kv.keys.ptable = ptable
+
+	for i, key := range keys {
+		kv.Put(key, vals[i])
+	}
+
+	return nil
+}
+
+// Save writes snapshot of the field, both data and configuration
+func (f *GF2[K]) Save(w io.Writer) error {
+	if err := writeHeader(w, kindGF2, f.arcs.desc, &f.arcs.ptable); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	buf := binary.AppendUvarint(nil, uint64(f.arcs.length))
+	for e := f.arcs.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		arc := e.Value
+		buf = binary.AppendUvarint(buf, uint64(arc.Rank))
+		buf = binary.AppendUvarint(buf, uint64(arc.Lo))
+		buf = binary.AppendUvarint(buf, uint64(arc.Hi))
+		buf = binary.AppendUvarint(buf, arc.Load)
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Load restores the field from snapshot, it replaces both data and configuration.
+// The snapshot is validated as Import does, the field remains unchanged on error.
+func (f *GF2[K]) Load(r io.Reader) error {
+	cr := newCountReader(r)
+
+	desc, ptable, err := readHeader(cr, kindGF2)
+	if err != nil {
+		return err
+	}
+
+	n, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return err
+	}
+
+	top := uint64(*new(K) - 1)
+	seq := make([]Arc[K], 0, min(n, 1<<16))
+	for i := uint64(0); i < n; i++ {
+		var x [4]uint64
+		for j := range x {
+			if x[j], err = binary.ReadUvarint(cr); err != nil {
+				return err
+			}
+		}
+
+		if x[0] > 64 || x[1] > top || x[2] > top {
+			return errMalformed
+		}
+
+		seq = append(seq, Arc[K]{Rank: uint32(x[0]), Lo: K(x[1]), Hi: K(x[2]), Load: x[3]})
+	}
+
+	arcs := NewMap[K, Arc[K]]()
+	if f.arcs != nil {
+		arcs = f.arcs.Clone()
+		arcs.Clear()
+	}
+
+	if arcs.desc != desc {
+		arcs.reverse()
+	}
+	arcs.ptable = ptable
+
+	return f.restore(arcs, seq)
+}
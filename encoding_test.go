@@ -0,0 +1,556 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+	"github.com/fogfish/skiplist/ord"
+)
+
+func TestGob(t *testing.T) {
+	kv := skiplist.NewMap[int, string]()
+	set := skiplist.NewSet[string]()
+	hm := skiplist.NewHashMap[int, string]()
+	for i := 0; i < 100; i++ {
+		kv.Put(i, string(rune('a'+i%26)))
+		set.Add(string(rune('a' + i%26)))
+		hm.Put(i, string(rune('a'+i%26)))
+	}
+
+	t.Run("Map", func(t *testing.T) {
+		type T struct {
+			Map *skiplist.Map[int, string]
+		}
+
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(T{Map: kv})
+		it.Then(t).Should(it.Nil(err))
+
+		var val T
+		err = gob.NewDecoder(&buf).Decode(&val)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.True(skiplist.Equal(kv, val.Map, func(a, b string) bool { return a == b })),
+		)
+	})
+
+	t.Run("MapWith", func(t *testing.T) {
+		desc := skiplist.NewMapWith[int, string](ord.Reverse[int](ord.Int))
+		for i := 0; i < 10; i++ {
+			desc.Put(i, "x")
+		}
+
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(desc)
+		it.Then(t).Should(it.Nil(err))
+
+		val := skiplist.NewMapWith[int, string](ord.Reverse[int](ord.Int))
+		err = gob.NewDecoder(&buf).Decode(val)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Seq(val.KeysSlice()).Equal(9, 8, 7, 6, 5, 4, 3, 2, 1, 0),
+		)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(set)
+		it.Then(t).Should(it.Nil(err))
+
+		val := new(skiplist.Set[string])
+		err = gob.NewDecoder(&buf).Decode(val)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.True(set.Equal(val)),
+		)
+	})
+
+	t.Run("HashMap", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(hm)
+		it.Then(t).Should(it.Nil(err))
+
+		val := new(skiplist.HashMap[int, string])
+		err = gob.NewDecoder(&buf).Decode(val)
+		it.Then(t).Should(it.Nil(err))
+
+		n := 0
+		for k, v := range val.All() {
+			x, _ := hm.Get(k)
+			it.Then(t).Should(it.Equal(v, x))
+			n++
+		}
+		it.Then(t).Should(it.Equal(n, 100))
+	})
+
+	t.Run("NamedKey", func(t *testing.T) {
+		type ID int
+
+		src := skiplist.NewMap[ID, int]()
+		src.Put(10, 10)
+		src.Put(2, 2)
+
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(src)
+		it.Then(t).Should(it.Nil(err))
+
+		val := new(skiplist.Map[ID, int])
+		err = gob.NewDecoder(&buf).Decode(val)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Seq(val.KeysSlice()).Equal(2, 10),
+		)
+	})
+
+	t.Run("UnknownOrder", func(t *testing.T) {
+		type ID struct{ N int }
+
+		src := skiplist.NewMapFunc[ID, int](func(a, b ID) int { return a.N - b.N })
+		src.Put(ID{1}, 1)
+
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(src)
+		it.Then(t).Should(it.Nil(err))
+
+		err = gob.NewDecoder(&buf).Decode(new(skiplist.Map[ID, int]))
+		it.Then(t).Should(
+			it.Equal(err, skiplist.ErrUnknownOrder),
+		)
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("Map", func(t *testing.T) {
+		kv := skiplist.NewMap[int, string]()
+		kv.Put(10, "c")
+		kv.Put(2, "b")
+		kv.Put(-1, "a")
+
+		b, err := json.Marshal(kv)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(string(b), `{"-1":"a","2":"b","10":"c"}`),
+		)
+
+		var val struct {
+			Map *skiplist.Map[int, string] `json:"map"`
+		}
+		err = json.Unmarshal([]byte(`{"map": {"10": "c", "-1": "a", "2": "b"}}`), &val)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Seq(val.Map.KeysSlice()).Equal(-1, 2, 10),
+			it.Seq(val.Map.ValuesSlice()).Equal("a", "b", "c"),
+		)
+	})
+
+	t.Run("MapOfStrings", func(t *testing.T) {
+		kv := skiplist.NewMap[string, int]()
+		kv.Put("b", 2)
+		kv.Put("a\"", 1)
+
+		b, err := json.Marshal(kv)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(string(b), `{"a\"":1,"b":2}`),
+		)
+
+		val := skiplist.NewMap[string, int]()
+		err = json.Unmarshal(b, val)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Seq(val.KeysSlice()).Equal("a\"", "b"),
+		)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		set := skiplist.NewSet[float64]()
+		set.AddAll(3.5, 1, 2)
+
+		b, err := json.Marshal(set)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(string(b), `[1,2,3.5]`),
+		)
+
+		val := new(skiplist.Set[float64])
+		err = json.Unmarshal([]byte(`[3, 1, 2, 1]`), val)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(val.Length(), 3),
+			it.Equal(val.At(0).Key, 1.0),
+		)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		val := skiplist.NewMap[int, string]()
+		it.Then(t).ShouldNot(
+			it.Nil(json.Unmarshal([]byte(`[]`), val)),
+			it.Nil(json.Unmarshal([]byte(`{"x": "a"}`), val)),
+		)
+	})
+}
+
+func TestBinary(t *testing.T) {
+	type ID uint32
+
+	t.Run("Map", func(t *testing.T) {
+		kv := skiplist.NewMap[string, float64]()
+		for i := 0; i < 1000; i++ {
+			kv.Put(strconv.Itoa(i), float64(i)/3)
+		}
+
+		var buf bytes.Buffer
+		n, err := kv.WriteTo(&buf)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(n, int64(buf.Len())),
+		)
+
+		size := buf.Len()
+		val, err := skiplist.ReadMapFrom[string, float64](&buf)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(val.Length(), 1000),
+			it.True(skiplist.Equal(kv, val, func(a, b float64) bool { return a == b })),
+			it.Equal(buf.Len(), 0),
+		)
+
+		m, err := skiplist.NewMap[string, float64]().ReadFrom(bytes.NewReader(make([]byte, size)))
+		it.Then(t).ShouldNot(it.Nil(err))
+		it.Then(t).Should(it.Equal(m, int64(4)))
+	})
+
+	t.Run("Types", func(t *testing.T) {
+		kv := skiplist.NewMap[ID, []byte]()
+		kv.Put(10, []byte("ten"))
+		kv.Put(1, nil)
+
+		var buf bytes.Buffer
+		_, err := kv.WriteTo(&buf)
+		it.Then(t).Should(it.Nil(err))
+
+		zero := new(skiplist.Map[ID, []byte])
+		_, err = zero.ReadFrom(bytes.NewReader(buf.Bytes()))
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Seq(zero.KeysSlice()).Equal(1, 10),
+		)
+
+		val := skiplist.NewMap[ID, []byte]()
+		_, err = val.ReadFrom(bytes.NewReader(buf.Bytes()))
+		v, _ := val.Get(10)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Seq(val.KeysSlice()).Equal(1, 10),
+			it.Equal(string(v), "ten"),
+		)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		kv := skiplist.NewMap[int, struct{}]()
+		kv.Put(1, struct{}{})
+
+		_, err := kv.WriteTo(&bytes.Buffer{})
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+
+	t.Run("NotSorted", func(t *testing.T) {
+		kv := skiplist.NewMap[int, int]()
+		kv.Put(1, 1)
+		kv.Put(2, 2)
+
+		var buf bytes.Buffer
+		_, err := kv.WriteTo(&buf)
+		it.Then(t).Should(it.Nil(err))
+
+		_, err = skiplist.NewMapWith[int, int](ord.Reverse[int](ord.Int)).ReadFrom(&buf)
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		huge := append([]byte("skl\x01\x01"), binary.AppendUvarint(nil, 1<<62)...)
+		long := append([]byte("skl\x01\x01"), binary.AppendUvarint(nil, 1<<20)...)
+
+		_, errHuge := skiplist.ReadMapFrom[string, int](bytes.NewReader(huge))
+		_, errLong := skiplist.ReadMapFrom[string, int](bytes.NewReader(append(long, "abc"...)))
+		it.Then(t).ShouldNot(
+			it.Nil(errHuge),
+			it.Nil(errLong),
+		)
+	})
+}
+
+func TestLoadSorted(t *testing.T) {
+	record := func(key string, val int) []byte {
+		b := []byte(key + "=" + strconv.Itoa(val))
+		return append(binary.AppendUvarint(nil, uint64(len(b))), b...)
+	}
+
+	decode := func(b []byte) (string, int, error) {
+		key, val, _ := strings.Cut(string(b), "=")
+		v, err := strconv.Atoi(val)
+		return key, v, err
+	}
+
+	t.Run("Stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		for i := 0; i < 1000; i++ {
+			buf.Write(record(fmt.Sprintf("%04d", i), i))
+		}
+
+		kv, err := skiplist.LoadSorted(&buf, decode)
+		v, _ := kv.Get("0500")
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(kv.Length(), 1000),
+			it.Equal(v, 500),
+			it.Equal(kv.At(999).Key, "0999"),
+		)
+
+		kv.Put("1000", 1000)
+		it.Then(t).Should(it.Equal(kv.Length(), 1001))
+	})
+
+	t.Run("NotSorted", func(t *testing.T) {
+		var buf bytes.Buffer
+		buf.Write(record("b", 2))
+		buf.Write(record("a", 1))
+
+		_, err := skiplist.LoadSorted(&buf, decode)
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		b := record("a", 1)
+		_, err := skiplist.LoadSorted(bytes.NewReader(b[:len(b)-1]), decode)
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		b := binary.AppendUvarint(nil, 1<<62)
+		_, err := skiplist.LoadSorted(bytes.NewReader(b), decode)
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+}
+
+type jsonCodec[V any] struct{}
+
+func (jsonCodec[V]) Encode(v V) ([]byte, error) { return json.Marshal(v) }
+func (jsonCodec[V]) Decode(b []byte) (V, error) {
+	var v V
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+func TestCodec(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	codec := skiplist.MapWithCodec[int](jsonCodec[T]{})
+	kv := skiplist.NewMap(codec)
+	for i := 0; i < 100; i++ {
+		kv.Put(i, T{Name: strconv.Itoa(i), Age: i})
+	}
+
+	eq := func(a, b T) bool { return a == b }
+
+	t.Run("Binary", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := kv.WriteTo(&buf)
+		it.Then(t).Should(it.Nil(err))
+
+		val, err := skiplist.ReadMapFrom(&buf, codec)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.True(skiplist.Equal(kv, val, eq)),
+		)
+	})
+
+	t.Run("Gob", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(kv)
+		it.Then(t).Should(it.Nil(err))
+
+		val := skiplist.NewMap(codec)
+		err = gob.NewDecoder(&buf).Decode(val)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.True(skiplist.Equal(kv, val, eq)),
+		)
+	})
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Run("Map", func(t *testing.T) {
+		kv := skiplist.NewMap(
+			skiplist.MapWithDescending[int, string](),
+			skiplist.MapWithBlockSize[int, string](16),
+		)
+		for i := 0; i < 100; i++ {
+			kv.Put(i, strconv.Itoa(i))
+		}
+
+		var buf bytes.Buffer
+		err := kv.Save(&buf)
+		it.Then(t).Should(it.Nil(err))
+
+		val := new(skiplist.Map[int, string])
+		err = val.Load(&buf)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.True(skiplist.Equal(kv, val, func(a, b string) bool { return a == b })),
+			it.Equal(val.At(0).Key, 99),
+		)
+
+		val.Put(100, "100")
+		it.Then(t).Should(
+			it.Equal(val.At(0).Key, 100),
+		)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		set := skiplist.NewSet(skiplist.SetWithDescending[string]())
+		set.AddAll("a", "b", "c")
+
+		var buf bytes.Buffer
+		err := set.Save(&buf)
+		it.Then(t).Should(it.Nil(err))
+
+		val := skiplist.NewSet[string]()
+		err = val.Load(&buf)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(val.At(0).Key, "c"),
+			it.True(set.Equal(val)),
+		)
+	})
+
+	t.Run("HashMap", func(t *testing.T) {
+		kv := skiplist.NewHashMap[uint8, bool]()
+		kv.Put(1, true)
+		kv.Put(2, false)
+
+		var buf bytes.Buffer
+		err := kv.Save(&buf)
+		it.Then(t).Should(it.Nil(err))
+
+		val := new(skiplist.HashMap[uint8, bool])
+		err = val.Load(&buf)
+		v1, _ := val.Get(1)
+		v2, has := val.Get(2)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(val.Length(), 2),
+			it.True(v1),
+			it.True(has),
+		).ShouldNot(
+			it.True(v2),
+		)
+	})
+
+	t.Run("GF2", func(t *testing.T) {
+		gf2 := skiplist.NewGF2[uint32]()
+		for i := 0; i < 10; i++ {
+			gf2.Add(uint32(i) * 1000000)
+			gf2.Hit(uint32(i)*7000000, uint64(i+1))
+		}
+
+		var buf bytes.Buffer
+		err := gf2.Save(&buf)
+		it.Then(t).Should(it.Nil(err))
+		snapshot := bytes.Clone(buf.Bytes())
+
+		val := new(skiplist.GF2[uint32])
+		err = val.Load(&buf)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(val.Length(), gf2.Length()),
+			it.Seq(val.Export()).Equal(gf2.Export()...),
+		)
+
+		narrow := skiplist.NewGF2[uint8]()
+		err = narrow.Load(bytes.NewReader(snapshot))
+		it.Then(t).Should(
+			it.Equal(narrow.Length(), 1),
+		).ShouldNot(
+			it.Nil(err),
+		)
+
+		for i := 0; i < 10; i++ {
+			a, _ := gf2.Get(uint32(i) * 1234567)
+			b, _ := val.Get(uint32(i) * 1234567)
+			it.Then(t).Should(it.Equal(a, b))
+		}
+	})
+
+	t.Run("Corrupted", func(t *testing.T) {
+		desc := skiplist.NewMap(skiplist.MapWithDescending[int, int]())
+		set := skiplist.NewSet(skiplist.SetWithDescending[int]())
+		hm := skiplist.NewHashMap[int, int]()
+		for i := 0; i < 10; i++ {
+			desc.Put(i, i)
+			set.Add(i)
+			hm.Put(i, i)
+		}
+
+		var mbuf, sbuf, hbuf bytes.Buffer
+		desc.Save(&mbuf)
+		set.Save(&sbuf)
+		hm.Save(&hbuf)
+
+		kv := skiplist.NewMap[int, int]()
+		kv.Put(1, 1)
+		kv.Put(2, 2)
+		err := kv.Load(bytes.NewReader(mbuf.Bytes()[:mbuf.Len()-1]))
+		it.Then(t).ShouldNot(it.Nil(err))
+		kv.Put(0, 0)
+		it.Then(t).Should(
+			it.Seq(kv.KeysSlice()).Equal(0, 1, 2),
+		)
+
+		val := skiplist.NewSet[int]()
+		val.AddAll(1, 2)
+		err = val.Load(bytes.NewReader(sbuf.Bytes()[:sbuf.Len()-1]))
+		it.Then(t).ShouldNot(it.Nil(err))
+		val.Add(0)
+		it.Then(t).Should(
+			it.Equal(val.At(0).Key, 0),
+			it.Equal(val.Length(), 3),
+		)
+
+		hv := skiplist.NewHashMap[int, int]()
+		hv.Put(1, 1)
+		err = hv.Load(bytes.NewReader(hbuf.Bytes()[:hbuf.Len()-1]))
+		it.Then(t).ShouldNot(it.Nil(err))
+		it.Then(t).Should(
+			it.Equal(hv.Length(), 1),
+		)
+	})
+
+	t.Run("Kind", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := skiplist.NewSet[int]().Save(&buf)
+		it.Then(t).Should(it.Nil(err))
+
+		err = skiplist.NewMap[int, int]().Load(&buf)
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+}
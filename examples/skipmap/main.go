@@ -30,11 +30,11 @@ func main() {
 	fmt.Println(skipmap)
 
 	// Get values
-	val, node := skipmap.Get(70)
-	fmt.Printf("==> value by (70) exists: %v %v\n", val, node)
+	val, has := skipmap.Get(70)
+	fmt.Printf("==> value by (70) exists: %v %v\n", val, has)
 
-	val, node = skipmap.Get(35)
-	fmt.Printf("==> value by (35) exists: %v %v\n", val, node)
+	val, has = skipmap.Get(35)
+	fmt.Printf("==> value by (35) exists: %v %v\n", val, has)
 
 	// Remove values
 	fmt.Println("\n==> remove (40) value")
@@ -30,11 +30,8 @@ func main() {
 	fmt.Println(skipset)
 
 	// Get values
-	has, node := skipset.Has("skipset")
-	fmt.Printf("==> value (skipset) exists: %v %v\n", has, node)
-
-	has, node = skipset.Has("rockset")
-	fmt.Printf("==> value (rockset) exists: %v %v\n", has, node)
+	fmt.Printf("==> value (skipset) exists: %v %v\n", skipset.Has("skipset"), skipset.Find("skipset"))
+	fmt.Printf("==> value (rockset) exists: %v %v\n", skipset.Has("rockset"), skipset.Find("rockset"))
 
 	// Remove values
 	fmt.Println("\n==> remove (new) value")
@@ -1,6 +1,6 @@
 module github.com/fogfish/skiplist
 
-go 1.20
+go 1.23
 
 require github.com/fogfish/it/v2 v2.0.1
 
@@ -0,0 +1,178 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import "iter"
+
+// All pairs of the map in key order
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *Map[K, V]) All() iter.Seq2[K, V] {
+	return kv.ascend(kv.head.Fingers[0])
+}
+
+// Keys of the map in order
+//
+//	for k := range kv.Keys() { /* ... */ }
+func (kv *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+			if !yield(e.Key) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq is values of the map ordered by keys, Values is the linked list
+// of pairs.
+//
+//	for v := range kv.ValuesSeq() { /* ... */ }
+func (kv *Map[K, V]) ValuesSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+			if !yield(e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Ascend iterates over pairs of the map starting from the key
+//
+//	for k, v := range kv.Ascend(key) { /* ... */ }
+func (kv *Map[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return kv.ascend(kv.Successor(from))
+}
+
+// Range iterates over pairs of the map with keys in the interval [from, to)
+//
+//	for k, v := range kv.Range(from, to) { /* ... */ }
+func (kv *Map[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := kv.Successor(from); e != nil && kv.compare(e.Key, to) < 0; e = e.Fingers[0] {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (kv *Map[K, V]) ascend(el *Pair[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := el; e != nil; e = e.Fingers[0] {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// All elements of the set in order
+//
+//	for k := range set.All() { /* ... */ }
+func (set *Set[K]) All() iter.Seq[K] {
+	return set.ascend(set.head.Fingers[0])
+}
+
+// Keys of the set in order, same as All
+//
+//	for k := range set.Keys() { /* ... */ }
+func (set *Set[K]) Keys() iter.Seq[K] {
+	return set.All()
+}
+
+// Ascend iterates over elements of the set starting from the key
+//
+//	for k := range set.Ascend(key) { /* ... */ }
+func (set *Set[K]) Ascend(from K) iter.Seq[K] {
+	return set.ascend(set.Successor(from))
+}
+
+// Range iterates over elements of the set in the interval [from, to)
+//
+//	for k := range set.Range(from, to) { /* ... */ }
+func (set *Set[K]) Range(from, to K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for e := set.Successor(from); e != nil && set.compare(e.Key, to) < 0; e = e.Fingers[0] {
+			if !yield(e.Key) {
+				return
+			}
+		}
+	}
+}
+
+func (set *Set[K]) ascend(el *Element[K]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for e := el; e != nil; e = e.Fingers[0] {
+			if !yield(e.Key) {
+				return
+			}
+		}
+	}
+}
+
+// All pairs of the map in key order. Values are kept apart from ordered keys,
+// each pair costs a hash lookup of the value. Use Map if range scans dominate
+// point lookups.
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *HashMap[K, V]) All() iter.Seq2[K, V] {
+	return kv.ascend(kv.keys.Values())
+}
+
+// KeysSeq is keys of the map in order, Keys is the linked list of keys.
+//
+//	for k := range kv.KeysSeq() { /* ... */ }
+func (kv *HashMap[K, V]) KeysSeq() iter.Seq[K] {
+	return kv.keys.All()
+}
+
+// Values of the map ordered by keys
+//
+//	for v := range kv.Values() { /* ... */ }
+func (kv *HashMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for e := kv.keys.Values(); e != nil; e = e.Fingers[0] {
+			if !yield(kv.values[e.Key]) {
+				return
+			}
+		}
+	}
+}
+
+// Ascend iterates over pairs of the map starting from the key
+//
+//	for k, v := range kv.Ascend(key) { /* ... */ }
+func (kv *HashMap[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return kv.ascend(kv.keys.Successor(from))
+}
+
+// Range iterates over pairs of the map with keys in the interval [from, to)
+//
+//	for k, v := range kv.Range(from, to) { /* ... */ }
+func (kv *HashMap[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := kv.keys.Successor(from); e != nil && kv.keys.compare(e.Key, to) < 0; e = e.Fingers[0] {
+			if !yield(e.Key, kv.values[e.Key]) {
+				return
+			}
+		}
+	}
+}
+
+func (kv *HashMap[K, V]) ascend(el *Element[K]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := el; e != nil; e = e.Fingers[0] {
+			if !yield(e.Key, kv.values[e.Key]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestIterMap(t *testing.T) {
+	kv := skiplist.NewMap[int, string]()
+	kv.Put(3, "c")
+	kv.Put(1, "a")
+	kv.Put(2, "b")
+
+	keys, vals := []int{}, []string{}
+	for k, v := range kv.All() {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+
+	from := []int{}
+	for k := range kv.Ascend(2) {
+		from = append(from, k)
+	}
+
+	some := []int{}
+	for k := range kv.Keys() {
+		some = append(some, k)
+		if k == 2 {
+			break
+		}
+	}
+
+	it.Then(t).Should(
+		it.Seq(keys).Equal(1, 2, 3),
+		it.Seq(vals).Equal("a", "b", "c"),
+		it.Seq(from).Equal(2, 3),
+		it.Seq(some).Equal(1, 2),
+		it.Seq(slices.Collect(kv.ValuesSeq())).Equal("a", "b", "c"),
+	)
+}
+
+func TestIterSet(t *testing.T) {
+	set := skiplist.NewSet[int]()
+	set.AddAll(3, 1, 2)
+
+	keys := []int{}
+	for k := range set.All() {
+		keys = append(keys, k)
+	}
+
+	from := []int{}
+	for k := range set.Ascend(2) {
+		from = append(from, k)
+		break
+	}
+
+	it.Then(t).Should(
+		it.Seq(keys).Equal(1, 2, 3),
+		it.Seq(from).Equal(2),
+		it.Seq(slices.Collect(set.Keys())).Equal(1, 2, 3),
+	)
+}
+
+func TestIterHashMap(t *testing.T) {
+	kv := skiplist.NewHashMap[int, string]()
+	kv.Put(3, "c")
+	kv.Put(1, "a")
+	kv.Put(2, "b")
+
+	keys, vals := []int{}, []string{}
+	for k, v := range kv.All() {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+
+	from := []string{}
+	for _, v := range kv.Ascend(2) {
+		from = append(from, v)
+	}
+
+	only := []string{}
+	for v := range kv.Values() {
+		only = append(only, v)
+	}
+
+	it.Then(t).Should(
+		it.Seq(keys).Equal(1, 2, 3),
+		it.Seq(vals).Equal("a", "b", "c"),
+		it.Seq(from).Equal("b", "c"),
+		it.Seq(only).Equal("a", "b", "c"),
+		it.Seq(slices.Collect(kv.KeysSeq())).Equal(1, 2, 3),
+	)
+}
+
+func TestIterRange(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	set := skiplist.NewSet[int]()
+	hm := skiplist.NewHashMap[int, int]()
+	for i := 0; i < 10; i++ {
+		kv.Put(i, i)
+		set.Add(i)
+		hm.Put(i, i)
+	}
+
+	mkeys, skeys, hkeys := []int{}, []int{}, []int{}
+	for k := range kv.Range(3, 6) {
+		mkeys = append(mkeys, k)
+	}
+	for k := range set.Range(-5, 2) {
+		skeys = append(skeys, k)
+	}
+	for k := range hm.Range(8, 20) {
+		hkeys = append(hkeys, k)
+	}
+
+	empty := 0
+	for range kv.Range(6, 3) {
+		empty++
+	}
+
+	it.Then(t).Should(
+		it.Seq(mkeys).Equal(3, 4, 5),
+		it.Seq(skeys).Equal(0, 1),
+		it.Seq(hkeys).Equal(8, 9),
+		it.Equal(empty, 0),
+	)
+}
@@ -13,21 +13,40 @@ import (
 	"github.com/fogfish/golem/trait/seq"
 )
 
+// Seq is iterator over keys that supports forward jumps
+type Seq[K any] interface {
+	seq.Seq[K]
+
+	// Seek moves iterator to the first key that is greater or equal to
+	// the given one, returns false if iterator is exhausted.
+	Seek(K) bool
+}
+
+// PairSeq is iterator over (key, value) pairs that supports forward jumps
+type PairSeq[K, V any] interface {
+	pair.Seq[K, V]
+
+	// Seek moves iterator to the first key that is greater or equal to
+	// the given one, returns false if iterator is exhausted.
+	Seek(K) bool
+}
+
 // Build generic iterate over Set elements
 //
 //	seq := skiplist.ForSet(set, set.Successor(key))
 //	for has := seq != nil; has; has = seq.Next() {
 //		seq.Key()
 //	}
-func ForSet[K Key](set *Set[K], el *Element[K]) seq.Seq[K] {
+func ForSet[K any](set *Set[K], el *Element[K]) Seq[K] {
 	if el == nil {
 		return nil
 	}
-	return &forSet[K]{el}
+	return &forSet[K]{set: set, el: el}
 }
 
-type forSet[K Key] struct {
-	el *Element[K]
+type forSet[K any] struct {
+	set *Set[K]
+	el  *Element[K]
 }
 
 func (it *forSet[K]) Value() K { return it.el.Key }
@@ -41,21 +60,32 @@ func (it *forSet[K]) Next() bool {
 	return it.el != nil
 }
 
+func (it *forSet[K]) Seek(key K) bool {
+	if it.el == nil {
+		return false
+	}
+
+	it.el = it.set.Seek(it.el, key)
+
+	return it.el != nil
+}
+
 // Iterate over Map elements
 //
 //	seq := skiplist.ForMap(kv, kv.Successor(key))
 //	for has := seq != nil; has; has = seq.Next() {
 //		seq.Key()
 //	}
-func ForMap[K Key, V any](kv *Map[K, V], el *Pair[K, V]) pair.Seq[K, V] {
+func ForMap[K any, V any](kv *Map[K, V], el *Pair[K, V]) PairSeq[K, V] {
 	if el == nil {
 		return nil
 	}
 
-	return &forMap[K, V]{el: el}
+	return &forMap[K, V]{kv: kv, el: el}
 }
 
-type forMap[K Key, V any] struct {
+type forMap[K any, V any] struct {
+	kv *Map[K, V]
 	el *Pair[K, V]
 }
 
@@ -71,32 +101,38 @@ func (it *forMap[K, V]) Next() bool {
 	return it.el != nil
 }
 
-func ForHashMap[K Key, V any](kv *HashMap[K, V], key *Element[K]) pair.Seq[K, V] {
-	if key == nil {
-		return nil
+func (it *forMap[K, V]) Seek(key K) bool {
+	if it.el == nil {
+		return false
 	}
 
-	val, _ := kv.Get(key.Key)
-	return &forHashMap[K, V]{key: key, val: val, kv: kv}
+	it.el = it.kv.Seek(it.el, key)
+
+	return it.el != nil
 }
 
-func ForGF2[K Num](gf2 *GF2[K], key *Element[K]) pair.Seq[K, Arc[K]] {
+func ForHashMap[K comparable, V any](kv *HashMap[K, V], key *Element[K]) PairSeq[K, V] {
 	if key == nil {
 		return nil
 	}
 
-	val, _ := gf2.Get(key.Key)
-	return &forHashMap[K, Arc[K]]{key: key, val: val, kv: gf2}
+	val, _ := kv.Get(key.Key)
+	return &forHashMap[K, V]{key: key, val: val, kv: kv, keys: kv.keys}
+}
+
+func ForGF2[K Num](gf2 *GF2[K], key *Pair[K, Arc[K]]) PairSeq[K, Arc[K]] {
+	return ForMap(gf2.arcs, key)
 }
 
-type getter[K Key, V any] interface {
+type getter[K comparable, V any] interface {
 	Get(K) (V, bool)
 }
 
-type forHashMap[K Key, V any] struct {
-	key *Element[K]
-	val V
-	kv  getter[K, V]
+type forHashMap[K comparable, V any] struct {
+	key  *Element[K]
+	val  V
+	kv   getter[K, V]
+	keys *Set[K]
 }
 
 func (it *forHashMap[K, V]) Key() K   { return it.key.Key }
@@ -115,3 +151,116 @@ func (it *forHashMap[K, V]) Next() bool {
 
 	return true
 }
+
+func (it *forHashMap[K, V]) Seek(key K) bool {
+	if it.key == nil {
+		return false
+	}
+
+	it.key = it.keys.Seek(it.key, key)
+	if it.key == nil {
+		return false
+	}
+
+	it.val, _ = it.kv.Get(it.key.Key)
+
+	return true
+}
+
+// Build iterator over Set elements on the level, it walks the express lane
+// of skip list starting from the element
+//
+//	seq := skiplist.ForSetOn(set.ValuesOn(level), level)
+//	for has := seq != nil; has; has = seq.Next() {
+//		seq.Value()
+//	}
+func ForSetOn[K any](el *Element[K], level int) seq.Seq[K] {
+	if el == nil || level < 0 || level >= len(el.Fingers) {
+		return nil
+	}
+
+	return &forSetOn[K]{el: el, level: level}
+}
+
+type forSetOn[K any] struct {
+	el    *Element[K]
+	level int
+}
+
+func (it *forSetOn[K]) Value() K { return it.el.Key }
+func (it *forSetOn[K]) Next() bool {
+	if it.el == nil {
+		return false
+	}
+
+	it.el = it.el.NextOn(it.level)
+
+	return it.el != nil
+}
+
+// Build iterator over Map elements on the level, it walks the express lane
+// of skip list starting from the element
+//
+//	seq := skiplist.ForMapOn(kv.ValuesOn(level), level)
+//	for has := seq != nil; has; has = seq.Next() {
+//		seq.Key()
+//	}
+func ForMapOn[K, V any](el *Pair[K, V], level int) pair.Seq[K, V] {
+	if el == nil || level < 0 || level >= len(el.Fingers) {
+		return nil
+	}
+
+	return &forMapOn[K, V]{el: el, level: level}
+}
+
+type forMapOn[K, V any] struct {
+	el    *Pair[K, V]
+	level int
+}
+
+func (it *forMapOn[K, V]) Key() K   { return it.el.Key }
+func (it *forMapOn[K, V]) Value() V { return it.el.Value }
+func (it *forMapOn[K, V]) Next() bool {
+	if it.el == nil {
+		return false
+	}
+
+	it.el = it.el.NextOn(it.level)
+
+	return it.el != nil
+}
+
+// Build iterator over HashMap elements on the level, it walks the express
+// lane of skip list starting from the key
+func ForHashMapOn[K comparable, V any](kv *HashMap[K, V], key *Element[K], level int) pair.Seq[K, V] {
+	if key == nil || level < 0 || level >= len(key.Fingers) {
+		return nil
+	}
+
+	val, _ := kv.Get(key.Key)
+	return &forHashMapOn[K, V]{key: key, val: val, kv: kv, level: level}
+}
+
+type forHashMapOn[K comparable, V any] struct {
+	key   *Element[K]
+	val   V
+	kv    *HashMap[K, V]
+	level int
+}
+
+func (it *forHashMapOn[K, V]) Key() K   { return it.key.Key }
+func (it *forHashMapOn[K, V]) Value() V { return it.val }
+func (it *forHashMapOn[K, V]) Next() bool {
+	if it.key == nil {
+		return false
+	}
+
+	it.key = it.key.NextOn(it.level)
+	if it.key == nil {
+		return false
+	}
+
+	it.val, _ = it.kv.Get(it.key.Key)
+
+	return true
+}
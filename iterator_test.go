@@ -259,3 +259,149 @@ func TestForMap(t *testing.T) {
 		)
 	})
 }
+
+func TestSeek(t *testing.T) {
+	set := skiplist.NewSet[int]()
+	kv := skiplist.NewMap[int, int]()
+	hm := skiplist.NewHashMap[int, int]()
+	for i := 0; i < 1000; i += 2 {
+		set.Add(i)
+		kv.Put(i, i)
+		hm.Put(i, i)
+	}
+
+	t.Run("Set", func(t *testing.T) {
+		seq := skiplist.ForSet(set, set.Values())
+		it.Then(t).Should(
+			it.True(seq.Seek(101)),
+			it.Equal(seq.Value(), 102),
+			it.True(seq.Seek(50)),
+			it.Equal(seq.Value(), 102),
+			it.True(seq.Seek(998)),
+			it.Equal(seq.Value(), 998),
+		).ShouldNot(
+			it.True(seq.Seek(999)),
+			it.True(seq.Next()),
+		)
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		seq := skiplist.ForMap(kv, kv.Values())
+		it.Then(t).Should(
+			it.True(seq.Seek(501)),
+			it.Equal(seq.Key(), 502),
+			it.Equal(seq.Value(), 502),
+			it.True(seq.Next()),
+			it.Equal(seq.Key(), 504),
+		).ShouldNot(
+			it.True(seq.Seek(1000)),
+		)
+
+		for i := 0; i < 999; i++ {
+			el := kv.Seek(kv.Values(), i)
+			it.Then(t).Should(
+				it.Equal(el.Key, i+i%2),
+			)
+		}
+	})
+
+	t.Run("HashMap", func(t *testing.T) {
+		seq := skiplist.ForHashMap(hm, hm.Keys())
+		it.Then(t).Should(
+			it.True(seq.Seek(301)),
+			it.Equal(seq.Key(), 302),
+			it.Equal(seq.Value(), 302),
+		).ShouldNot(
+			it.True(seq.Seek(1000)),
+		)
+	})
+
+	t.Run("SubMap", func(t *testing.T) {
+		sub := kv.SubMap(100, 200)
+		seq := skiplist.ForSubMap(sub, sub.Values())
+		it.Then(t).Should(
+			it.True(seq.Seek(151)),
+			it.Equal(seq.Key(), 152),
+		).ShouldNot(
+			it.True(seq.Seek(201)),
+		)
+	})
+}
+
+func TestForLevel(t *testing.T) {
+	set := skiplist.NewSet[int]()
+	kv := skiplist.NewMap[int, int]()
+	hm := skiplist.NewHashMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		set.Add(i)
+		kv.Put(i, i)
+		hm.Put(i, i)
+	}
+
+	t.Run("Set", func(t *testing.T) {
+		for level := 0; level <= set.Level(); level++ {
+			keys := []int{}
+			for e := set.ValuesOn(level); e != nil; e = e.NextOn(level) {
+				keys = append(keys, e.Key)
+			}
+
+			seq := skiplist.ForSetOn(set.ValuesOn(level), level)
+			for i, has := 0, seq != nil; has; i, has = i+1, seq.Next() {
+				it.Then(t).Should(
+					it.Equal(seq.Value(), keys[i]),
+				)
+			}
+
+			it.Then(t).Should(
+				it.True(len(keys) > 0),
+				it.True(sort.IntsAreSorted(keys)),
+			)
+			if level == 0 {
+				it.Then(t).Should(it.Equal(len(keys), 1000))
+			}
+		}
+
+		it.Then(t).Should(
+			it.True(set.ValuesOn(set.Level()+1) == nil),
+			it.True(set.ValuesOn(-1) == nil),
+			it.Nil(skiplist.ForSetOn(set.ValuesOn(set.Level()+1), set.Level()+1)),
+			it.Nil(skiplist.ForSetOn(set.ValuesOn(0), -1)),
+			it.Nil(skiplist.ForSetOn(set.ValuesOn(0), skiplist.L)),
+		)
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		level := kv.Level()
+		n := 0
+		seq := skiplist.ForMapOn(kv.ValuesOn(level), level)
+		for has := seq != nil; has; has = seq.Next() {
+			it.Then(t).Should(
+				it.Equal(seq.Key(), seq.Value()),
+				it.True(seq.Key() >= 0 && seq.Key() < 1000),
+			)
+			n++
+		}
+		it.Then(t).Should(
+			it.True(n > 0 && n < 1000),
+			it.Nil(skiplist.ForMapOn(kv.ValuesOn(0), -1)),
+			it.Nil(skiplist.ForMapOn(kv.ValuesOn(0), skiplist.L)),
+		)
+	})
+
+	t.Run("HashMap", func(t *testing.T) {
+		level := 1
+		n := 0
+		seq := skiplist.ForHashMapOn(hm, hm.KeysOn(level), level)
+		for has := seq != nil; has; has = seq.Next() {
+			it.Then(t).Should(
+				it.Equal(seq.Key(), seq.Value()),
+			)
+			n++
+		}
+		it.Then(t).Should(
+			it.True(n > 0 && n < 1000),
+			it.Nil(skiplist.ForHashMapOn(hm, hm.KeysOn(0), -1)),
+			it.Nil(skiplist.ForHashMapOn(hm, hm.KeysOn(0), skiplist.L)),
+		)
+	})
+}
@@ -0,0 +1,156 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"container/heap"
+
+	"github.com/fogfish/golem/trait/pair"
+	"github.com/fogfish/golem/trait/seq"
+	"github.com/fogfish/skiplist/ord"
+)
+
+// MergeSeqs builds a single sorted iterator over multiple sorted iterators
+// (e.g. iterators of several maps). The pick function resolves values of
+// duplicate keys, it receives the accumulated value and the value of the next
+// iterator. If pick is nil then duplicate keys are emitted in order of iterators.
+func MergeSeqs[K, V any](cmp ord.Ord[K], pick func(K, V, V) V, seqs ...pair.Seq[K, V]) pair.Seq[K, V] {
+	m := &mergeSeqs[K, V]{cmp: cmp, pick: pick}
+	m.heap.compare = func(a, b pair.Seq[K, V]) int { return cmp.Compare(a.Key(), b.Key()) }
+
+	for i, s := range seqs {
+		if s != nil {
+			m.heap.seq = append(m.heap.seq, cursor[pair.Seq[K, V]]{seq: s, id: i})
+		}
+	}
+
+	if len(m.heap.seq) == 0 {
+		return nil
+	}
+
+	heap.Init(&m.heap)
+	m.pop()
+
+	return m
+}
+
+type mergeSeqs[K, V any] struct {
+	heap cursors[pair.Seq[K, V]]
+	cmp  ord.Ord[K]
+	pick func(K, V, V) V
+	key  K
+	val  V
+}
+
+func (m *mergeSeqs[K, V]) Key() K   { return m.key }
+func (m *mergeSeqs[K, V]) Value() V { return m.val }
+func (m *mergeSeqs[K, V]) Next() bool {
+	if len(m.heap.seq) == 0 {
+		return false
+	}
+
+	m.pop()
+	return true
+}
+
+func (m *mergeSeqs[K, V]) pop() {
+	c := heap.Pop(&m.heap).(cursor[pair.Seq[K, V]])
+	m.key, m.val = c.seq.Key(), c.seq.Value()
+	m.heap.advance(c)
+
+	if m.pick == nil {
+		return
+	}
+
+	for len(m.heap.seq) > 0 && m.cmp.Compare(m.heap.seq[0].seq.Key(), m.key) == 0 {
+		c := heap.Pop(&m.heap).(cursor[pair.Seq[K, V]])
+		m.val = m.pick(m.key, m.val, c.seq.Value())
+		m.heap.advance(c)
+	}
+}
+
+// MergeKeys builds a single sorted iterator over multiple sorted iterators
+// of keys (e.g. iterators of several sets). Duplicate keys are emitted in
+// order of iterators.
+func MergeKeys[K any](cmp ord.Ord[K], seqs ...seq.Seq[K]) seq.Seq[K] {
+	m := &mergeKeys[K]{}
+	m.heap.compare = func(a, b seq.Seq[K]) int { return cmp.Compare(a.Value(), b.Value()) }
+
+	for i, s := range seqs {
+		if s != nil {
+			m.heap.seq = append(m.heap.seq, cursor[seq.Seq[K]]{seq: s, id: i})
+		}
+	}
+
+	if len(m.heap.seq) == 0 {
+		return nil
+	}
+
+	heap.Init(&m.heap)
+	m.pop()
+
+	return m
+}
+
+type mergeKeys[K any] struct {
+	heap cursors[seq.Seq[K]]
+	key  K
+}
+
+func (m *mergeKeys[K]) Value() K { return m.key }
+func (m *mergeKeys[K]) Next() bool {
+	if len(m.heap.seq) == 0 {
+		return false
+	}
+
+	m.pop()
+	return true
+}
+
+func (m *mergeKeys[K]) pop() {
+	c := heap.Pop(&m.heap).(cursor[seq.Seq[K]])
+	m.key = c.seq.Value()
+	m.heap.advance(c)
+}
+
+// cursor is an active iterator within the heap
+type cursor[S interface{ Next() bool }] struct {
+	seq S
+	id  int
+}
+
+// cursors implements heap.Interface, iterators are ordered by the current key
+// and by position at input for equal keys
+type cursors[S interface{ Next() bool }] struct {
+	seq     []cursor[S]
+	compare func(S, S) int
+}
+
+func (h cursors[S]) Len() int      { return len(h.seq) }
+func (h cursors[S]) Swap(i, j int) { h.seq[i], h.seq[j] = h.seq[j], h.seq[i] }
+func (h cursors[S]) Less(i, j int) bool {
+	if c := h.compare(h.seq[i].seq, h.seq[j].seq); c != 0 {
+		return c < 0
+	}
+	return h.seq[i].id < h.seq[j].id
+}
+
+func (h *cursors[S]) Push(x any) { h.seq = append(h.seq, x.(cursor[S])) }
+func (h *cursors[S]) Pop() any {
+	n := len(h.seq)
+	x := h.seq[n-1]
+	h.seq = h.seq[:n-1]
+	return x
+}
+
+func (h *cursors[S]) advance(c cursor[S]) {
+	if c.seq.Next() {
+		heap.Push(h, c)
+	}
+}
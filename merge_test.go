@@ -0,0 +1,99 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+	"github.com/fogfish/skiplist/ord"
+)
+
+func TestMergeSeqs(t *testing.T) {
+	a := skiplist.NewMap[int, string]()
+	b := skiplist.NewMap[int, string]()
+	c := skiplist.NewMap[int, string]()
+	for i := 0; i < 10; i++ {
+		switch i % 3 {
+		case 0:
+			a.Put(i, "a")
+		case 1:
+			b.Put(i, "b")
+		default:
+			c.Put(i, "c")
+		}
+	}
+	a.Put(4, "a")
+
+	t.Run("Duplicates", func(t *testing.T) {
+		seq := skiplist.MergeSeqs[int, string](ord.Int, nil,
+			skiplist.ForMap(a, a.Values()),
+			skiplist.ForMap(b, b.Values()),
+			skiplist.ForMap(c, c.Values()),
+		)
+
+		keys, vals := []int{}, []string{}
+		for has := seq != nil; has; has = seq.Next() {
+			keys = append(keys, seq.Key())
+			vals = append(vals, seq.Value())
+		}
+
+		it.Then(t).Should(
+			it.Seq(keys).Equal(0, 1, 2, 3, 4, 4, 5, 6, 7, 8, 9),
+			it.Seq(vals).Equal("a", "b", "c", "a", "a", "b", "c", "a", "b", "c", "a"),
+		)
+	})
+
+	t.Run("Pick", func(t *testing.T) {
+		seq := skiplist.MergeSeqs(ord.Int,
+			func(k int, x, y string) string { return x + y },
+			skiplist.ForMap(a, a.Values()),
+			skiplist.ForMap(b, b.Values()),
+			nil,
+			skiplist.ForMap(c, c.Values()),
+		)
+
+		keys, vals := []int{}, []string{}
+		for has := seq != nil; has; has = seq.Next() {
+			keys = append(keys, seq.Key())
+			vals = append(vals, seq.Value())
+		}
+
+		it.Then(t).Should(
+			it.Seq(keys).Equal(0, 1, 2, 3, 4, 5, 6, 7, 8, 9),
+			it.Seq(vals).Equal("a", "b", "c", "a", "ab", "c", "a", "b", "c", "a"),
+		)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Nil(skiplist.MergeSeqs[int, string](ord.Int, nil)),
+			it.Nil(skiplist.MergeSeqs[int, string](ord.Int, nil, nil, nil)),
+		)
+	})
+}
+
+func TestMergeKeys(t *testing.T) {
+	a := skiplist.NewSet[int]()
+	a.AddAll(1, 3, 5, 7)
+
+	b := skiplist.NewSet[int]()
+	b.AddAll(2, 3, 6)
+
+	seq := skiplist.MergeKeys[int](ord.Int,
+		skiplist.ForSet(a, a.Values()),
+		skiplist.ForSet(b, b.Values()),
+	)
+
+	it.Then(t).Should(
+		it.Seq(valuesOf(seq)).Equal(1, 2, 3, 3, 5, 6, 7),
+		it.Nil(skiplist.MergeKeys[int](ord.Int)),
+	)
+}
@@ -0,0 +1,65 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package ord
+
+// Natural ordering of strings, embedded decimal numbers are compared by
+// value ("item2" < "item10"). Numbers of equal value but different leading
+// zeros are ordered by byte-wise order of strings, distinct strings are
+// never equal.
+var Natural = From[string](compareNatural)
+
+func compareNatural(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			x, y := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+
+			if c := compareNumber(a[x:i], b[y:j]); c != 0 {
+				return c
+			}
+			continue
+		}
+
+		if a[i] != b[j] {
+			return Uint8.Compare(a[i], b[j])
+		}
+		i++
+		j++
+	}
+
+	if c := Int.Compare(len(a)-i, len(b)-j); c != 0 {
+		return c
+	}
+
+	return String.Compare(a, b)
+}
+
+// compare decimal numbers of arbitrary length
+func compareNumber(a, b string) int {
+	for len(a) > 1 && a[0] == '0' {
+		a = a[1:]
+	}
+	for len(b) > 1 && b[0] == '0' {
+		b = b[1:]
+	}
+
+	if c := Int.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+
+	return String.Compare(a, b)
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
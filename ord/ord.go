@@ -0,0 +1,257 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+// Package ord defines total ordering of values used by skip structures
+package ord
+
+import (
+	"bytes"
+	"math"
+	"net/netip"
+	"time"
+)
+
+// Ord type class defines total ordering of values. Compare returns
+// negative value if a < b, zero if a == b and positive value if a > b.
+type Ord[T any] interface {
+	Compare(a, b T) int
+}
+
+// From lifts compare function into Ord type class
+//
+//	ord.From[T](func(a, b T) int { ... })
+type From[T any] func(a, b T) int
+
+func (f From[T]) Compare(a, b T) int { return f(a, b) }
+
+// Reverse ordering of the type class
+func Reverse[T any](ord Ord[T]) Ord[T] { return reverse[T]{ord} }
+
+type reverse[T any] struct{ Ord[T] }
+
+func (r reverse[T]) Compare(a, b T) int { return r.Ord.Compare(b, a) }
+
+// Ordered is constraint on types that supports < operator
+type Ordered interface {
+	~string |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Type builds ordering of types that supports < operator.
+// NaN is not ordered by < operator, use Float64Total or Float32Total
+// for float keys that might be NaN.
+type Type[T Ordered] struct{}
+
+func (Type[T]) Compare(a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case b < a:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Orderings of built-in types
+var (
+	String  = Type[string]{}
+	Int     = Type[int]{}
+	Int8    = Type[int8]{}
+	Int16   = Type[int16]{}
+	Int32   = Type[int32]{}
+	Int64   = Type[int64]{}
+	Uint    = Type[uint]{}
+	Uint8   = Type[uint8]{}
+	Uint16  = Type[uint16]{}
+	Uint32  = Type[uint32]{}
+	Uint64  = Type[uint64]{}
+	Float32 = Type[float32]{}
+	Float64 = Type[float64]{}
+)
+
+// Total ordering of floats as defined by IEEE 754 totalOrder predicate:
+// -NaN < -Inf < ... < -0 < +0 < ... < +Inf < +NaN. Unlike < operator,
+// NaN is equal to itself and -0 precedes +0.
+var (
+	Float32Total = From[float32](func(a, b float32) int {
+		return Int32.Compare(totalBits32(a), totalBits32(b))
+	})
+
+	Float64Total = From[float64](func(a, b float64) int {
+		return Int64.Compare(totalBits64(a), totalBits64(b))
+	})
+)
+
+// flips magnitude bits of negative floats, the result is ordered as integer
+func totalBits32(f float32) int32 {
+	x := int32(math.Float32bits(f))
+	return x ^ int32(uint32(x>>31)>>1)
+}
+
+func totalBits64(f float64) int64 {
+	x := int64(math.Float64bits(f))
+	return x ^ int64(uint64(x>>63)>>1)
+}
+
+// Bytes is lexicographic ordering of byte slices, it is consistent with
+// ordering of strings. Skip structures keep slices by reference, keys must
+// not be modified after insert.
+var Bytes = From[[]byte](bytes.Compare)
+
+// ID16 builds big-endian lexicographic ordering of 16-byte identifiers
+// (e.g. UUID or ULID types defined as [16]byte). Time-ordered identifiers
+// (ULID, UUIDv7) are ordered by time.
+type ID16[T ~[16]byte] struct{}
+
+func (ID16[T]) Compare(a, b T) int {
+	x, y := [16]byte(a), [16]byte(b)
+	return bytes.Compare(x[:], y[:])
+}
+
+// UUID is ordering of [16]byte identifiers
+var UUID = ID16[[16]byte]{}
+
+// Orderings of IP addresses, IPv4 addresses are ordered before IPv6.
+// Prefixes are ordered by masked address then by length, so that a network
+// precedes its subnets and range lookups of addresses are possible.
+var (
+	Addr   = From[netip.Addr](netip.Addr.Compare)
+	Prefix = From[netip.Prefix](comparePrefix)
+)
+
+func comparePrefix(a, b netip.Prefix) int {
+	if c := a.Masked().Addr().Compare(b.Masked().Addr()); c != 0 {
+		return c
+	}
+
+	return Int.Compare(a.Bits(), b.Bits())
+}
+
+// Orderings of time
+var (
+	Time     = From[time.Time](time.Time.Compare)
+	Duration = Type[time.Duration]{}
+)
+
+// T2 is composite key of two components
+type T2[A, B any] struct {
+	V1 A
+	V2 B
+}
+
+// Tuple2 builds lexicographic ordering of composite key from orderings
+// of components
+//
+//	ord.Tuple2(ord.String, ord.Int64)
+func Tuple2[A, B any](a Ord[A], b Ord[B]) Ord[T2[A, B]] {
+	return From[T2[A, B]](func(x, y T2[A, B]) int {
+		if c := a.Compare(x.V1, y.V1); c != 0 {
+			return c
+		}
+
+		return b.Compare(x.V2, y.V2)
+	})
+}
+
+// T3 is composite key of three components
+type T3[A, B, C any] struct {
+	V1 A
+	V2 B
+	V3 C
+}
+
+// Tuple3 builds lexicographic ordering of composite key from orderings
+// of components
+//
+//	ord.Tuple3(ord.String, ord.Int64, ord.String)
+func Tuple3[A, B, C any](a Ord[A], b Ord[B], c Ord[C]) Ord[T3[A, B, C]] {
+	return From[T3[A, B, C]](func(x, y T3[A, B, C]) int {
+		if n := a.Compare(x.V1, y.V1); n != 0 {
+			return n
+		}
+
+		if n := b.Compare(x.V2, y.V2); n != 0 {
+			return n
+		}
+
+		return c.Compare(x.V3, y.V3)
+	})
+}
+
+// Then builds ordering by primary, ties are broken by secondary ordering
+//
+//	ord.Then(ord.Reverse(byScore), byName)
+func Then[T any](primary, secondary Ord[T]) Ord[T] {
+	return From[T](func(a, b T) int {
+		if c := primary.Compare(a, b); c != 0 {
+			return c
+		}
+
+		return secondary.Compare(a, b)
+	})
+}
+
+// Collation adapts collator (e.g. locale-aware collate.Collator.CompareString
+// or case-insensitive comparison) into ordering of strings. Collators treat
+// distinct strings as equal (e.g. "a" and "A"), such strings would collide
+// as a single key of skip structure. Collation breaks these ties by byte-wise
+// order, distinct strings are never equal. Use From if equivalent strings
+// shall be merged into single key.
+func Collation(collate func(a, b string) int) Ord[string] {
+	return From[string](func(a, b string) int {
+		if c := collate(a, b); c != 0 {
+			return c
+		}
+
+		return String.Compare(a, b)
+	})
+}
+
+// Comparer builds ordering of types implementing Compare method
+//
+//	ord.Comparer[time.Time]()
+func Comparer[T interface{ Compare(T) int }]() Ord[T] { return comparer[T]{} }
+
+type comparer[T interface{ Compare(T) int }] struct{}
+
+func (comparer[T]) Compare(a, b T) int { return a.Compare(b) }
+
+// Ptr builds ordering of pointers by ordering of referenced values,
+// nil pointer precedes any other pointer
+func Ptr[T any](ord Ord[T]) Ord[*T] {
+	return From[*T](func(a, b *T) int { return comparePtr(ord, a, b, -1) })
+}
+
+// PtrNilLast builds ordering of pointers by ordering of referenced values,
+// nil pointer follows any other pointer
+func PtrNilLast[T any](ord Ord[T]) Ord[*T] {
+	return From[*T](func(a, b *T) int { return comparePtr(ord, a, b, 1) })
+}
+
+func comparePtr[T any](ord Ord[T], a, b *T, null int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return null
+	case b == nil:
+		return -null
+	default:
+		return ord.Compare(*a, *b)
+	}
+}
+
+// ContraMap builds ordering of A from ordering of key derived by f
+//
+//	ord.ContraMap(func(u User) int64 { return u.LastSeen }, ord.Int64)
+func ContraMap[A, B any](f func(A) B, ord Ord[B]) Ord[A] {
+	return From[A](func(a, b A) int { return ord.Compare(f(a), f(b)) })
+}
@@ -0,0 +1,229 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package ord_test
+
+import (
+	"math"
+	"net/netip"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist/ord"
+)
+
+func TestType(t *testing.T) {
+	it.Then(t).Should(
+		it.Equal(ord.Int.Compare(1, 2), -1),
+		it.Equal(ord.Int.Compare(2, 2), 0),
+		it.Equal(ord.Int.Compare(2, 1), 1),
+		it.Equal(ord.String.Compare("a", "b"), -1),
+		it.Equal(ord.Float64.Compare(1.5, 1.0), 1),
+	)
+}
+
+func TestFrom(t *testing.T) {
+	fold := ord.From[string](func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	it.Then(t).Should(
+		it.Equal(fold.Compare("a", "B"), -1),
+		it.Equal(fold.Compare("A", "a"), 0),
+		it.Equal(fold.Compare("b", "A"), 1),
+	)
+}
+
+func TestReverse(t *testing.T) {
+	rev := ord.Reverse[int](ord.Int)
+
+	it.Then(t).Should(
+		it.Equal(rev.Compare(1, 2), 1),
+		it.Equal(rev.Compare(2, 2), 0),
+		it.Equal(rev.Compare(2, 1), -1),
+	)
+}
+
+func TestTuple(t *testing.T) {
+	t2 := ord.Tuple2(ord.String, ord.Int)
+	t3 := ord.Tuple3(ord.String, ord.Int64, ord.Reverse[int](ord.Int))
+
+	it.Then(t).Should(
+		it.Equal(t2.Compare(ord.T2[string, int]{"a", 2}, ord.T2[string, int]{"b", 1}), -1),
+		it.Equal(t2.Compare(ord.T2[string, int]{"a", 2}, ord.T2[string, int]{"a", 1}), 1),
+		it.Equal(t2.Compare(ord.T2[string, int]{"a", 1}, ord.T2[string, int]{"a", 1}), 0),
+		it.Equal(t3.Compare(ord.T3[string, int64, int]{"a", 1, 1}, ord.T3[string, int64, int]{"a", 1, 2}), 1),
+		it.Equal(t3.Compare(ord.T3[string, int64, int]{"a", 1, 1}, ord.T3[string, int64, int]{"a", 2, 0}), -1),
+		it.Equal(t3.Compare(ord.T3[string, int64, int]{"a", 1, 1}, ord.T3[string, int64, int]{"a", 1, 1}), 0),
+	)
+}
+
+func TestContraMap(t *testing.T) {
+	type user struct {
+		name string
+		age  int
+	}
+
+	byAge := ord.ContraMap(func(u user) int { return u.age }, ord.Int)
+
+	it.Then(t).Should(
+		it.Equal(byAge.Compare(user{"a", 30}, user{"b", 20}), 1),
+		it.Equal(byAge.Compare(user{"a", 20}, user{"b", 20}), 0),
+		it.Equal(byAge.Compare(user{"a", 10}, user{"b", 20}), -1),
+	)
+}
+
+func TestTime(t *testing.T) {
+	now := time.Now()
+
+	it.Then(t).Should(
+		it.Equal(ord.Time.Compare(now, now.Add(time.Second)), -1),
+		it.Equal(ord.Time.Compare(now, now.UTC()), 0),
+		it.Equal(ord.Time.Compare(now.Add(time.Second), now), 1),
+		it.Equal(ord.Duration.Compare(time.Second, time.Minute), -1),
+	)
+}
+
+func TestBytes(t *testing.T) {
+	it.Then(t).Should(
+		it.Equal(ord.Bytes.Compare([]byte("ab"), []byte("b")), -1),
+		it.Equal(ord.Bytes.Compare([]byte("ab"), []byte("a")), 1),
+		it.Equal(ord.Bytes.Compare([]byte{0x01, 0xff}, []byte{0x02}), -1),
+		it.Equal(ord.Bytes.Compare(nil, []byte{}), 0),
+	)
+}
+
+func TestAddr(t *testing.T) {
+	addr := netip.MustParseAddr
+	prefix := netip.MustParsePrefix
+
+	it.Then(t).Should(
+		it.Equal(ord.Addr.Compare(addr("10.0.0.2"), addr("10.0.0.10")), -1),
+		it.Equal(ord.Addr.Compare(addr("255.255.255.255"), addr("::1")), -1),
+		it.Equal(ord.Addr.Compare(addr("::2"), addr("::1")), 1),
+		it.Equal(ord.Prefix.Compare(prefix("10.0.0.0/8"), prefix("10.1.0.0/16")), -1),
+		it.Equal(ord.Prefix.Compare(prefix("10.0.0.0/8"), prefix("10.0.0.0/16")), -1),
+		it.Equal(ord.Prefix.Compare(prefix("10.0.0.1/8"), prefix("10.0.0.0/8")), 0),
+		it.Equal(ord.Prefix.Compare(prefix("11.0.0.0/8"), prefix("10.1.0.0/16")), 1),
+		it.Equal(ord.Prefix.Compare(prefix("10.0.0.0/8"), prefix("::/0")), -1),
+	)
+}
+
+func TestUUID(t *testing.T) {
+	type ULID [16]byte
+
+	a := [16]byte{0x01, 0x8f}
+	b := [16]byte{0x01, 0x90}
+	c := [16]byte{0x01, 0x8f, 15: 0x01}
+
+	it.Then(t).Should(
+		it.Equal(ord.UUID.Compare(a, b), -1),
+		it.Equal(ord.UUID.Compare(c, a), 1),
+		it.Equal(ord.UUID.Compare(a, a), 0),
+		it.Equal(ord.ID16[ULID]{}.Compare(ULID(b), ULID(c)), 1),
+	)
+}
+
+func TestThen(t *testing.T) {
+	type player struct {
+		name  string
+		score int
+	}
+
+	byScore := ord.ContraMap(func(p player) int { return p.score }, ord.Int)
+	byName := ord.ContraMap(func(p player) string { return p.name }, ord.String)
+	rank := ord.Then(ord.Reverse(byScore), byName)
+
+	it.Then(t).Should(
+		it.Equal(rank.Compare(player{"a", 10}, player{"b", 20}), 1),
+		it.Equal(rank.Compare(player{"a", 10}, player{"b", 10}), -1),
+		it.Equal(rank.Compare(player{"a", 10}, player{"a", 10}), 0),
+	)
+}
+
+func TestCollation(t *testing.T) {
+	fold := ord.Collation(func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	it.Then(t).Should(
+		it.Equal(fold.Compare("a", "B"), -1),
+		it.Equal(fold.Compare("b", "A"), 1),
+		it.Equal(fold.Compare("A", "a"), -1),
+		it.Equal(fold.Compare("a", "A"), 1),
+		it.Equal(fold.Compare("a", "a"), 0),
+	)
+}
+
+func TestNatural(t *testing.T) {
+	it.Then(t).Should(
+		it.Equal(ord.Natural.Compare("item2", "item10"), -1),
+		it.Equal(ord.Natural.Compare("item10", "item2"), 1),
+		it.Equal(ord.Natural.Compare("item10", "item10"), 0),
+		it.Equal(ord.Natural.Compare("v1.10.0", "v1.9.2"), 1),
+		it.Equal(ord.Natural.Compare("a", "a1"), -1),
+		it.Equal(ord.Natural.Compare("a01", "a1"), -1),
+		it.Equal(ord.Natural.Compare("a1b", "a01c"), -1),
+		it.Equal(ord.Natural.Compare("99999999999999999999x", "100000000000000000000"), -1),
+	)
+}
+
+func TestPtr(t *testing.T) {
+	a, b := 1, 2
+	first := ord.Ptr[int](ord.Int)
+	last := ord.PtrNilLast[int](ord.Int)
+
+	it.Then(t).Should(
+		it.Equal(first.Compare(&a, &b), -1),
+		it.Equal(first.Compare(nil, &a), -1),
+		it.Equal(first.Compare(&a, nil), 1),
+		it.Equal(first.Compare(nil, nil), 0),
+		it.Equal(last.Compare(&b, &a), 1),
+		it.Equal(last.Compare(nil, &a), 1),
+		it.Equal(last.Compare(&a, nil), -1),
+		it.Equal(last.Compare(nil, nil), 0),
+	)
+}
+
+func TestComparer(t *testing.T) {
+	now := time.Now()
+	cmp := ord.Comparer[time.Time]()
+
+	it.Then(t).Should(
+		it.Equal(cmp.Compare(now, now.Add(time.Second)), -1),
+		it.Equal(cmp.Compare(now, now), 0),
+		it.Equal(cmp.Compare(now.Add(time.Second), now), 1),
+	)
+}
+
+func TestFloatTotal(t *testing.T) {
+	nan := math.NaN()
+	neg := math.Copysign(nan, -1)
+	inf := math.Inf(1)
+
+	seq := []float64{nan, 1, neg, 0, -inf, math.Copysign(0, -1), inf, -1}
+	sort.Slice(seq, func(i, j int) bool { return ord.Float64Total.Compare(seq[i], seq[j]) < 0 })
+
+	it.Then(t).Should(
+		it.True(math.IsNaN(seq[0]) && math.Signbit(seq[0])),
+		it.Equal(seq[1], -inf),
+		it.Equal(seq[2], -1.0),
+		it.True(seq[3] == 0 && math.Signbit(seq[3])),
+		it.True(seq[4] == 0 && !math.Signbit(seq[4])),
+		it.Equal(seq[5], 1.0),
+		it.Equal(seq[6], inf),
+		it.True(math.IsNaN(seq[7]) && !math.Signbit(seq[7])),
+		it.Equal(ord.Float64Total.Compare(nan, nan), 0),
+		it.Equal(ord.Float32Total.Compare(float32(nan), 1), 1),
+		it.Equal(ord.Float32Total.Compare(-2, -1), -1),
+		it.Equal(ord.Float32Total.Compare(1, 2), -1),
+	)
+}
@@ -0,0 +1,89 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import "iter"
+
+// RangeByPrefix yields pairs which keys starts with the prefix. Keys are
+// expected in ascending byte-wise order of strings (default ordering).
+//
+//	for k, v := range skiplist.RangeByPrefix(kv, "user/") { /* ... */ }
+func RangeByPrefix[K ~string, V any](kv *Map[K, V], prefix K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		end, bounded := prefixEnd(prefix)
+		for e := kv.Successor(prefix); e != nil && (!bounded || e.Key < end); e = e.Next() {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// CutPrefix removes all pairs which keys starts with the prefix,
+// returns number of removed pairs.
+func CutPrefix[K ~string, V any](kv *Map[K, V], prefix K) int {
+	if end, bounded := prefixEnd(prefix); bounded {
+		return kv.CutRange(prefix, end)
+	}
+
+	n := 0
+	for e := kv.Successor(prefix); e != nil; e = kv.Successor(prefix) {
+		kv.Cut(e.Key)
+		n++
+	}
+
+	return n
+}
+
+// RangeSetByPrefix yields keys which starts with the prefix. Keys are
+// expected in ascending byte-wise order of strings (default ordering).
+//
+//	for k := range skiplist.RangeSetByPrefix(set, "user/") { /* ... */ }
+func RangeSetByPrefix[K ~string](set *Set[K], prefix K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		end, bounded := prefixEnd(prefix)
+		for e := set.Successor(prefix); e != nil && (!bounded || e.Key < end); e = e.Next() {
+			if !yield(e.Key) {
+				return
+			}
+		}
+	}
+}
+
+// CutSetPrefix removes all keys which starts with the prefix,
+// returns number of removed keys.
+func CutSetPrefix[K ~string](set *Set[K], prefix K) int {
+	if end, bounded := prefixEnd(prefix); bounded {
+		return set.CutRange(prefix, end)
+	}
+
+	n := 0
+	for e := set.Successor(prefix); e != nil; e = set.Successor(prefix) {
+		set.Cut(e.Key)
+		n++
+	}
+
+	return n
+}
+
+// prefixEnd returns the least string greater than all strings with the
+// prefix. Trailing 0xFF bytes are dropped and the last byte is incremented,
+// the bound works at byte level and it is correct for multi-byte runes.
+// Empty prefix or prefix of 0xFF bytes has no bound.
+func prefixEnd[K ~string](prefix K) (K, bool) {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			b := []byte(prefix[:i+1])
+			b[i]++
+			return K(b), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,54 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestPrefix(t *testing.T) {
+	keys := []string{"a", "ab", "abc", "abd", "ac", "b", "é", "éa", "éé", "f", "\xff", "\xff\xff", "\xff\xffa"}
+
+	kv := skiplist.NewMap[string, int]()
+	set := skiplist.NewSet[string]()
+	for i, k := range keys {
+		kv.Put(k, i)
+		set.Add(k)
+	}
+
+	prefixOf := func(prefix string) []string {
+		out := []string{}
+		for k := range skiplist.RangeByPrefix(kv, prefix) {
+			out = append(out, k)
+		}
+		return out
+	}
+
+	it.Then(t).Should(
+		it.Seq(prefixOf("ab")).Equal("ab", "abc", "abd"),
+		it.Seq(prefixOf("é")).Equal("é", "éa", "éé"),
+		it.Seq(prefixOf("\xff\xff")).Equal("\xff\xff", "\xff\xffa"),
+		it.Equal(len(prefixOf("")), len(keys)),
+		it.Equal(len(prefixOf("x")), 0),
+		it.Seq(slices.Collect(skiplist.RangeSetByPrefix(set, "a"))).Equal("a", "ab", "abc", "abd", "ac"),
+	)
+
+	it.Then(t).Should(
+		it.Equal(skiplist.CutPrefix(kv, "é"), 3),
+		it.Equal(skiplist.CutPrefix(kv, "\xff"), 3),
+		it.Equal(kv.Length(), len(keys)-6),
+		it.Seq(prefixOf("")).Equal("a", "ab", "abc", "abd", "ac", "b", "f"),
+		it.Equal(skiplist.CutSetPrefix(set, "ab"), 3),
+		it.Seq(slices.Collect(skiplist.RangeSetByPrefix(set, "a"))).Equal("a", "ac"),
+	)
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import "sync/atomic"
+
+// Probe records comparisons of keys and hops between nodes made by Get,
+// Put and Cut operations of the map. It helps to compare probability
+// settings and to detect pathological distributions of keys.
+//
+// Counters are updated atomically, the probe is safe for concurrent readers
+// of the map (e.g. Get of SyncMap). Comparisons made by concurrent readers
+// are attributed to the operation approximately. Read counters once the map
+// is quiescent.
+type Probe struct {
+	Get ProbeStats
+	Put ProbeStats
+	Cut ProbeStats
+
+	// counters of the operation in progress
+	op     atomic.Pointer[ProbeStats]
+	active atomic.Int32
+}
+
+// ProbeStats is counters of the operation
+type ProbeStats struct {
+	// number of operations
+	Count uint64
+
+	// number of comparisons of keys
+	Comparisons uint64
+
+	// number of forward hops between nodes
+	Hops uint64
+}
+
+// AvgComparisons per operation
+func (s ProbeStats) AvgComparisons() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+
+	return float64(s.Comparisons) / float64(s.Count)
+}
+
+// AvgHops per operation
+func (s ProbeStats) AvgHops() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+
+	return float64(s.Hops) / float64(s.Count)
+}
+
+// Reset counters
+func (p *Probe) Reset() {
+	for _, s := range []*ProbeStats{&p.Get, &p.Put, &p.Cut} {
+		atomic.StoreUint64(&s.Count, 0)
+		atomic.StoreUint64(&s.Comparisons, 0)
+		atomic.StoreUint64(&s.Hops, 0)
+	}
+}
+
+const (
+	probeGet = iota
+	probePut
+	probeCut
+)
+
+// enter the operation, it is safe to call on nil probe
+func (p *Probe) enter(op int) {
+	if p == nil {
+		return
+	}
+
+	var s *ProbeStats
+	switch op {
+	case probeGet:
+		s = &p.Get
+	case probePut:
+		s = &p.Put
+	case probeCut:
+		s = &p.Cut
+	}
+
+	p.active.Add(1)
+	p.op.Store(s)
+	atomic.AddUint64(&s.Count, 1)
+}
+
+// exit the operation, the last one of concurrent operations stops counting
+func (p *Probe) exit() {
+	if p.active.Add(-1) == 0 {
+		p.op.Store(nil)
+	}
+}
+
+// probed wraps comparator with counters of probe. The search moves
+// forward each time the key of node is less than the key.
+func probed[K any](p *Probe, compare func(K, K) int) func(K, K) int {
+	return func(a, b K) int {
+		r := compare(a, b)
+		if s := p.op.Load(); s != nil {
+			atomic.AddUint64(&s.Comparisons, 1)
+			if r < 0 {
+				atomic.AddUint64(&s.Hops, 1)
+			}
+		}
+		return r
+	}
+}
+
+// ordering of keys without instrumentation of the probe
+func (kv *Map[K, V]) ordering() func(K, K) int {
+	if kv.unprobed != nil {
+		return kv.unprobed
+	}
+	return kv.compare
+}
@@ -0,0 +1,96 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestProbe(t *testing.T) {
+	probe := &skiplist.Probe{}
+	kv := skiplist.NewMap(skiplist.MapWithProbe[int, int](probe))
+
+	for i := 0; i < 10000; i++ {
+		kv.Put(i, i)
+	}
+	for i := 0; i < 10000; i++ {
+		kv.Get(i)
+	}
+	kv.Cut(5000)
+	kv.Successor(100)
+
+	it.Then(t).Should(
+		it.Equal(probe.Put.Count, uint64(10000)),
+		it.Equal(probe.Get.Count, uint64(10000)),
+		it.Equal(probe.Cut.Count, uint64(1)),
+		it.Greater(probe.Get.AvgComparisons(), 5.0),
+		it.Less(probe.Get.AvgComparisons(), 100.0),
+		it.Greater(probe.Get.Comparisons, probe.Get.Hops),
+		it.Greater(probe.Cut.Hops, uint64(0)),
+	)
+
+	probe.Reset()
+	it.Then(t).Should(
+		it.Equal(probe.Get.Count, uint64(0)),
+		it.Equal(probe.Get.AvgHops(), 0.0),
+	)
+}
+
+func TestProbeConcurrentReads(t *testing.T) {
+	probe := &skiplist.Probe{}
+	kv := skiplist.NewSyncMap(skiplist.MapWithProbe[int, int](probe))
+	for i := 0; i < 1000; i++ {
+		kv.Put(i, i)
+	}
+
+	// readers share the probe, run with -race
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < 1000; i += 8 {
+				kv.Get(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	it.Then(t).Should(
+		it.Equal(probe.Get.Count, uint64(1000)),
+		it.Greater(probe.Get.Comparisons, uint64(0)),
+	)
+}
+
+func TestProbeSnapshot(t *testing.T) {
+	probe := &skiplist.Probe{}
+	kv := skiplist.NewMap(
+		skiplist.MapWithProbe[int, int](probe),
+		skiplist.MapWithDescending[int, int](),
+	)
+	for i := 0; i < 100; i++ {
+		kv.Put(i, i)
+	}
+
+	snap := kv.Snapshot()
+	probe.Reset()
+	for i := 0; i < 100; i++ {
+		snap.Get(i)
+	}
+
+	it.Then(t).Should(
+		it.Equal(probe.Get.Count, uint64(0)),
+		it.Equal(probe.Get.Comparisons, uint64(0)),
+		it.Seq(snap.KeysSlice()[:3]).Equal(99, 98, 97),
+	)
+}
@@ -0,0 +1,455 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fogfish/golem/trait/pair"
+	"github.com/fogfish/golem/trait/seq"
+)
+
+// ErrStop is returned by visitor of ForEach to terminate iteration early.
+// ForEach does not report it as failure, it returns nil.
+var ErrStop = errors.New("stop iteration")
+
+// Take first n pairs from iterator
+func Take[K, V any](seq pair.Seq[K, V], n int) pair.Seq[K, V] {
+	if seq == nil || n <= 0 {
+		return nil
+	}
+
+	return &takePairs[K, V]{Seq: seq, n: n}
+}
+
+type takePairs[K, V any] struct {
+	pair.Seq[K, V]
+	n int
+}
+
+func (seq *takePairs[K, V]) Next() bool {
+	if seq.n <= 1 {
+		seq.n = 0
+		return false
+	}
+
+	seq.n--
+	return seq.Seq.Next()
+}
+
+// Drop first n pairs from iterator
+func Drop[K, V any](seq pair.Seq[K, V], n int) pair.Seq[K, V] {
+	if seq == nil {
+		return nil
+	}
+
+	for i := 0; i < n; i++ {
+		if !seq.Next() {
+			return nil
+		}
+	}
+
+	return seq
+}
+
+// Page of pairs from iterator, it skips offset pairs and takes limit pairs
+func Page[K, V any](seq pair.Seq[K, V], offset, limit int) pair.Seq[K, V] {
+	return Take(Drop(seq, offset), limit)
+}
+
+// TakeKeys takes first n keys from iterator
+func TakeKeys[K any](seq seq.Seq[K], n int) seq.Seq[K] {
+	if seq == nil || n <= 0 {
+		return nil
+	}
+
+	return &takeKeys[K]{Seq: seq, n: n}
+}
+
+type takeKeys[K any] struct {
+	seq.Seq[K]
+	n int
+}
+
+func (seq *takeKeys[K]) Next() bool {
+	if seq.n <= 1 {
+		seq.n = 0
+		return false
+	}
+
+	seq.n--
+	return seq.Seq.Next()
+}
+
+// DropKeys drops first n keys from iterator
+func DropKeys[K any](seq seq.Seq[K], n int) seq.Seq[K] {
+	if seq == nil {
+		return nil
+	}
+
+	for i := 0; i < n; i++ {
+		if !seq.Next() {
+			return nil
+		}
+	}
+
+	return seq
+}
+
+// PageKeys returns page of keys from iterator, it skips offset keys and
+// takes limit keys
+func PageKeys[K any](seq seq.Seq[K], offset, limit int) seq.Seq[K] {
+	return TakeKeys(DropKeys(seq, offset), limit)
+}
+
+// TakeWhile takes pairs from iterator while predicate is true
+func TakeWhile[K, V any](seq pair.Seq[K, V], f func(K, V) bool) pair.Seq[K, V] {
+	return pair.TakeWhile(seq, f)
+}
+
+// DropWhile drops pairs from iterator while predicate is true
+func DropWhile[K, V any](seq pair.Seq[K, V], f func(K, V) bool) pair.Seq[K, V] {
+	return pair.DropWhile(seq, f)
+}
+
+// Filter pairs of iterator that satisfy predicate
+func Filter[K, V any](seq pair.Seq[K, V], f func(K, V) bool) pair.Seq[K, V] {
+	return pair.Filter(seq, f)
+}
+
+// FMap transforms values of iterator
+func FMap[K, A, B any](seq pair.Seq[K, A], f func(K, A) B) pair.Seq[K, B] {
+	return pair.Map(seq, f)
+}
+
+// ForEach applies f to pairs of iterator, it stops at the first error of f.
+// Return ErrStop from f to break iteration without error.
+func ForEach[K, V any](seq pair.Seq[K, V], f func(K, V) error) error {
+	return stopped(pair.ForEach(seq, f))
+}
+
+// TakeWhileKeys takes keys from iterator while predicate is true
+func TakeWhileKeys[K any](keys seq.Seq[K], f func(K) bool) seq.Seq[K] {
+	return seq.TakeWhile(keys, f)
+}
+
+// DropWhileKeys drops keys from iterator while predicate is true
+func DropWhileKeys[K any](keys seq.Seq[K], f func(K) bool) seq.Seq[K] {
+	return seq.DropWhile(keys, f)
+}
+
+// FilterKeys filters keys of iterator that satisfy predicate
+func FilterKeys[K any](keys seq.Seq[K], f func(K) bool) seq.Seq[K] {
+	return seq.Filter(keys, f)
+}
+
+// FMapKeys transforms keys of iterator
+func FMapKeys[A, B any](keys seq.Seq[A], f func(A) B) seq.Seq[B] {
+	return seq.Map(keys, f)
+}
+
+// ForEachKeys applies f to keys of iterator, it stops at the first error of f.
+// Return ErrStop from f to break iteration without error.
+func ForEachKeys[K any](keys seq.Seq[K], f func(K) error) error {
+	return stopped(seq.ForEach(keys, f))
+}
+
+func stopped(err error) error {
+	if errors.Is(err, ErrStop) {
+		return nil
+	}
+
+	return err
+}
+
+// Descending iterates pairs in reverse order. The adapter buffers the entire
+// iterator, it takes O(n) memory.
+func Descending[K, V any](seq pair.Seq[K, V]) pair.Seq[K, V] {
+	if seq == nil {
+		return nil
+	}
+
+	desc := &descPairs[K, V]{}
+	for has := true; has; has = seq.Next() {
+		desc.keys = append(desc.keys, seq.Key())
+		desc.vals = append(desc.vals, seq.Value())
+	}
+	desc.at = len(desc.keys) - 1
+
+	return desc
+}
+
+type descPairs[K, V any] struct {
+	at   int
+	keys []K
+	vals []V
+}
+
+func (seq *descPairs[K, V]) Key() K   { return seq.keys[seq.at] }
+func (seq *descPairs[K, V]) Value() V { return seq.vals[seq.at] }
+func (seq *descPairs[K, V]) Next() bool {
+	if seq.at <= 0 {
+		return false
+	}
+
+	seq.at--
+	return true
+}
+
+// DescendingKeys iterates keys in reverse order. The adapter buffers
+// the entire iterator, it takes O(n) memory.
+func DescendingKeys[K any](seq seq.Seq[K]) seq.Seq[K] {
+	if seq == nil {
+		return nil
+	}
+
+	desc := &descKeys[K]{}
+	for has := true; has; has = seq.Next() {
+		desc.keys = append(desc.keys, seq.Value())
+	}
+	desc.at = len(desc.keys) - 1
+
+	return desc
+}
+
+type descKeys[K any] struct {
+	at   int
+	keys []K
+}
+
+func (seq *descKeys[K]) Value() K { return seq.keys[seq.at] }
+func (seq *descKeys[K]) Next() bool {
+	if seq.at <= 0 {
+		return false
+	}
+
+	seq.at--
+	return true
+}
+
+// Fold pairs of iterator into accumulator
+func Fold[K, V, A any](seq pair.Seq[K, V], acc A, f func(A, K, V) A) A {
+	for has := seq != nil; has; has = seq.Next() {
+		acc = f(acc, seq.Key(), seq.Value())
+	}
+
+	return acc
+}
+
+// Reduce pairs of iterator, the value of first pair is used as initial
+// accumulator. It returns false if iterator is empty.
+func Reduce[K, V any](seq pair.Seq[K, V], f func(V, K, V) V) (V, bool) {
+	if seq == nil {
+		return *new(V), false
+	}
+
+	acc := seq.Value()
+	for seq.Next() {
+		acc = f(acc, seq.Key(), seq.Value())
+	}
+
+	return acc, true
+}
+
+// FoldKeys folds keys of iterator into accumulator
+func FoldKeys[K, A any](seq seq.Seq[K], acc A, f func(A, K) A) A {
+	for has := seq != nil; has; has = seq.Next() {
+		acc = f(acc, seq.Value())
+	}
+
+	return acc
+}
+
+// ReduceKeys reduces keys of iterator, the first key is used as initial
+// accumulator. It returns false if iterator is empty.
+func ReduceKeys[K any](seq seq.Seq[K], f func(K, K) K) (K, bool) {
+	if seq == nil {
+		return *new(K), false
+	}
+
+	acc := seq.Value()
+	for seq.Next() {
+		acc = f(acc, seq.Value())
+	}
+
+	return acc, true
+}
+
+// ForEachCtx applies f to pairs of iterator until the context is cancelled,
+// it returns ctx.Err() on cancellation or the first error of f other than
+// ErrStop.
+func ForEachCtx[K, V any](ctx context.Context, seq pair.Seq[K, V], f func(K, V) error) error {
+	for has := seq != nil; has; has = seq.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := f(seq.Key(), seq.Value()); err != nil {
+			return stopped(err)
+		}
+	}
+
+	return nil
+}
+
+// ForEachCtxKeys applies f to keys of iterator until the context is cancelled,
+// it returns ctx.Err() on cancellation or the first error of f other than
+// ErrStop.
+func ForEachCtxKeys[K any](ctx context.Context, seq seq.Seq[K], f func(K) error) error {
+	for has := seq != nil; has; has = seq.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := f(seq.Value()); err != nil {
+			return stopped(err)
+		}
+	}
+
+	return nil
+}
+
+// Chunks groups pairs of iterator into batches of up to n pairs
+func Chunks[K, V any](seq pair.Seq[K, V], n int) seq.Seq[[]Pair[K, V]] {
+	if seq == nil || n <= 0 {
+		return nil
+	}
+
+	chunks := &chunkPairs[K, V]{src: seq, n: n}
+	chunks.Next()
+
+	return chunks
+}
+
+type chunkPairs[K, V any] struct {
+	src   pair.Seq[K, V]
+	n     int
+	chunk []Pair[K, V]
+}
+
+func (seq *chunkPairs[K, V]) Value() []Pair[K, V] { return seq.chunk }
+func (seq *chunkPairs[K, V]) Next() bool {
+	if seq.src == nil {
+		return false
+	}
+
+	seq.chunk = make([]Pair[K, V], 0, seq.n)
+	for len(seq.chunk) < seq.n {
+		seq.chunk = append(seq.chunk, Pair[K, V]{Key: seq.src.Key(), Value: seq.src.Value()})
+		if !seq.src.Next() {
+			seq.src = nil
+			break
+		}
+	}
+
+	return true
+}
+
+// ChunksKeys groups keys of iterator into batches of up to n keys
+func ChunksKeys[K any](seq seq.Seq[K], n int) seq.Seq[[]K] {
+	if seq == nil || n <= 0 {
+		return nil
+	}
+
+	chunks := &chunkKeys[K]{src: seq, n: n}
+	chunks.Next()
+
+	return chunks
+}
+
+type chunkKeys[K any] struct {
+	src   seq.Seq[K]
+	n     int
+	chunk []K
+}
+
+func (seq *chunkKeys[K]) Value() []K { return seq.chunk }
+func (seq *chunkKeys[K]) Next() bool {
+	if seq.src == nil {
+		return false
+	}
+
+	seq.chunk = make([]K, 0, seq.n)
+	for len(seq.chunk) < seq.n {
+		seq.chunk = append(seq.chunk, seq.src.Value())
+		if !seq.src.Next() {
+			seq.src = nil
+			break
+		}
+	}
+
+	return true
+}
+
+// Dedup collapses consecutive pairs with equal keys, the first pair wins
+func Dedup[K comparable, V any](seq pair.Seq[K, V]) pair.Seq[K, V] {
+	return DedupBy(seq, func(a, b K) bool { return a == b })
+}
+
+// DedupBy collapses consecutive pairs with keys equal by eq, the first pair wins
+func DedupBy[K, V any](seq pair.Seq[K, V], eq func(K, K) bool) pair.Seq[K, V] {
+	if seq == nil {
+		return nil
+	}
+
+	return &dedupPairs[K, V]{src: seq, eq: eq, key: seq.Key(), val: seq.Value()}
+}
+
+type dedupPairs[K, V any] struct {
+	src pair.Seq[K, V]
+	eq  func(K, K) bool
+	key K
+	val V
+}
+
+func (seq *dedupPairs[K, V]) Key() K   { return seq.key }
+func (seq *dedupPairs[K, V]) Value() V { return seq.val }
+func (seq *dedupPairs[K, V]) Next() bool {
+	for seq.src.Next() {
+		if !seq.eq(seq.key, seq.src.Key()) {
+			seq.key, seq.val = seq.src.Key(), seq.src.Value()
+			return true
+		}
+	}
+
+	return false
+}
+
+// DedupKeys collapses consecutive equal keys
+func DedupKeys[K comparable](seq seq.Seq[K]) seq.Seq[K] {
+	return DedupByKeys(seq, func(a, b K) bool { return a == b })
+}
+
+// DedupByKeys collapses consecutive keys equal by eq
+func DedupByKeys[K any](seq seq.Seq[K], eq func(K, K) bool) seq.Seq[K] {
+	if seq == nil {
+		return nil
+	}
+
+	return &dedupKeys[K]{src: seq, eq: eq, key: seq.Value()}
+}
+
+type dedupKeys[K any] struct {
+	src seq.Seq[K]
+	eq  func(K, K) bool
+	key K
+}
+
+func (seq *dedupKeys[K]) Value() K { return seq.key }
+func (seq *dedupKeys[K]) Next() bool {
+	for seq.src.Next() {
+		if !seq.eq(seq.key, seq.src.Value()) {
+			seq.key = seq.src.Value()
+			return true
+		}
+	}
+
+	return false
+}
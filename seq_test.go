@@ -0,0 +1,336 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/fogfish/golem/trait/pair"
+	"github.com/fogfish/golem/trait/seq"
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+	"github.com/fogfish/skiplist/ord"
+)
+
+func keysOf[K, V any](seq pair.Seq[K, V]) []K {
+	keys := []K{}
+	for has := seq != nil; has; has = seq.Next() {
+		keys = append(keys, seq.Key())
+	}
+	return keys
+}
+
+func valuesOf[K any](seq seq.Seq[K]) []K {
+	keys := []K{}
+	for has := seq != nil; has; has = seq.Next() {
+		keys = append(keys, seq.Value())
+	}
+	return keys
+}
+
+func TestPage(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	set := skiplist.NewSet[int]()
+	for i := 0; i < 10; i++ {
+		kv.Put(i, i)
+		set.Add(i)
+	}
+
+	mapSeq := func() pair.Seq[int, int] { return skiplist.ForMap(kv, kv.Values()) }
+	setSeq := func() seq.Seq[int] { return skiplist.ForSet(set, set.Values()) }
+
+	t.Run("Take", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Seq(keysOf(skiplist.Take(mapSeq(), 3))).Equal(0, 1, 2),
+			it.Seq(keysOf(skiplist.Take(mapSeq(), 20))).Equal(0, 1, 2, 3, 4, 5, 6, 7, 8, 9),
+			it.Nil(skiplist.Take(mapSeq(), 0)),
+			it.Seq(valuesOf(skiplist.TakeKeys(setSeq(), 3))).Equal(0, 1, 2),
+			it.Nil(skiplist.TakeKeys(setSeq(), 0)),
+		)
+	})
+
+	t.Run("Drop", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Seq(keysOf(skiplist.Drop(mapSeq(), 7))).Equal(7, 8, 9),
+			it.Nil(skiplist.Drop(mapSeq(), 10)),
+			it.Seq(valuesOf(skiplist.DropKeys(setSeq(), 7))).Equal(7, 8, 9),
+			it.Nil(skiplist.DropKeys(setSeq(), 10)),
+		)
+	})
+
+	t.Run("Page", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Seq(keysOf(skiplist.Page(mapSeq(), 3, 3))).Equal(3, 4, 5),
+			it.Seq(keysOf(skiplist.Page(mapSeq(), 8, 3))).Equal(8, 9),
+			it.Nil(skiplist.Page(mapSeq(), 10, 3)),
+			it.Seq(valuesOf(skiplist.PageKeys(setSeq(), 3, 3))).Equal(3, 4, 5),
+		)
+	})
+}
+
+func TestDescending(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	set := skiplist.NewSet[int]()
+	for i := 0; i < 5; i++ {
+		kv.Put(i, i)
+		set.Add(i)
+	}
+
+	desc := skiplist.Descending(skiplist.ForMap(kv, kv.Successor(1)))
+	vals := []int{}
+	for has := desc != nil; has; has = desc.Next() {
+		vals = append(vals, desc.Value())
+	}
+
+	it.Then(t).Should(
+		it.Seq(keysOf(skiplist.Descending(skiplist.ForMap(kv, kv.Values())))).Equal(4, 3, 2, 1, 0),
+		it.Seq(vals).Equal(4, 3, 2, 1),
+		it.Seq(valuesOf(skiplist.DescendingKeys(skiplist.ForSet(set, set.Values())))).Equal(4, 3, 2, 1, 0),
+		it.Nil(skiplist.Descending[int, int](nil)),
+		it.Nil(skiplist.DescendingKeys[int](nil)),
+	)
+}
+
+func TestFold(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	set := skiplist.NewSet[int]()
+	for i := 1; i <= 5; i++ {
+		kv.Put(i, 10*i)
+		set.Add(i)
+	}
+
+	sum := func(acc int, k, v int) int { return acc + k + v }
+	str := func(acc string, k int) string { return acc + strconv.Itoa(k) }
+	hi := func(a, b int) int {
+		if a > b {
+			return a
+		}
+		return b
+	}
+
+	red, ok := skiplist.Reduce(skiplist.ForMap(kv, kv.Values()), func(acc, k, v int) int { return acc + v })
+	_, none := skiplist.Reduce[int, int](nil, func(acc, k, v int) int { return acc + v })
+	top, has := skiplist.ReduceKeys(skiplist.ForSet(set, set.Values()), hi)
+
+	it.Then(t).Should(
+		it.Equal(skiplist.Fold(skiplist.ForMap(kv, kv.Values()), 0, sum), 165),
+		it.Equal(skiplist.Fold(nil, 7, sum), 7),
+		it.Equal(skiplist.FoldKeys(skiplist.ForSet(set, set.Values()), "", str), "12345"),
+		it.True(ok),
+		it.Equal(red, 150),
+		it.True(has),
+		it.Equal(top, 5),
+	).ShouldNot(
+		it.True(none),
+	)
+}
+
+func TestChunks(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	set := skiplist.NewSet[int]()
+	for i := 0; i < 7; i++ {
+		kv.Put(i, i)
+		set.Add(i)
+	}
+
+	sizes, keys := []int{}, []int{}
+	seq := skiplist.Chunks(skiplist.ForMap(kv, kv.Values()), 3)
+	for has := seq != nil; has; has = seq.Next() {
+		sizes = append(sizes, len(seq.Value()))
+		for _, x := range seq.Value() {
+			keys = append(keys, x.Key)
+		}
+	}
+
+	chunks := [][]int{}
+	kseq := skiplist.ChunksKeys(skiplist.ForSet(set, set.Values()), 7)
+	for has := kseq != nil; has; has = kseq.Next() {
+		chunks = append(chunks, kseq.Value())
+	}
+
+	it.Then(t).Should(
+		it.Seq(sizes).Equal(3, 3, 1),
+		it.Seq(keys).Equal(0, 1, 2, 3, 4, 5, 6),
+		it.Equal(len(chunks), 1),
+		it.Seq(chunks[0]).Equal(0, 1, 2, 3, 4, 5, 6),
+		it.Nil(skiplist.Chunks(skiplist.ForMap(kv, kv.Values()), 0)),
+		it.Nil(skiplist.ChunksKeys[int](nil, 3)),
+	)
+}
+
+func TestDedup(t *testing.T) {
+	a := skiplist.NewMap[int, string]()
+	b := skiplist.NewMap[int, string]()
+	for i := 0; i < 6; i++ {
+		a.Put(i, "a")
+		if i%2 == 0 {
+			b.Put(i, "b")
+		}
+	}
+
+	seq := skiplist.Dedup(
+		skiplist.MergeSeqs[int, string](ord.Int, nil,
+			skiplist.ForMap(a, a.Values()),
+			skiplist.ForMap(b, b.Values()),
+		),
+	)
+
+	keys, vals := []int{}, []string{}
+	for has := seq != nil; has; has = seq.Next() {
+		keys = append(keys, seq.Key())
+		vals = append(vals, seq.Value())
+	}
+
+	set := skiplist.NewSetFromSorted([]int{1, 5, 12, 17, 19, 21})
+	tens := skiplist.DedupByKeys(
+		skiplist.ForSet(set, set.Values()),
+		func(x, y int) bool { return x/10 == y/10 },
+	)
+
+	it.Then(t).Should(
+		it.Seq(keys).Equal(0, 1, 2, 3, 4, 5),
+		it.Seq(vals).Equal("a", "a", "a", "a", "a", "a"),
+		it.Seq(valuesOf(tens)).Equal(1, 12, 21),
+		it.Nil(skiplist.DedupKeys[int](nil)),
+	)
+}
+
+func TestForEachCtx(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	set := skiplist.NewSet[int]()
+	for i := 0; i < 10; i++ {
+		kv.Put(i, i)
+		set.Add(i)
+	}
+
+	t.Run("Complete", func(t *testing.T) {
+		n := 0
+		err := skiplist.ForEachCtx(context.Background(), skiplist.ForMap(kv, kv.Values()),
+			func(k, v int) error { n++; return nil },
+		)
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(n, 10),
+		)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		n := 0
+		err := skiplist.ForEachCtxKeys(ctx, skiplist.ForSet(set, set.Values()),
+			func(k int) error {
+				n++
+				if k == 4 {
+					cancel()
+				}
+				return nil
+			},
+		)
+
+		it.Then(t).Should(
+			it.True(errors.Is(err, context.Canceled)),
+			it.Equal(n, 5),
+		)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		fail := errors.New("fail")
+		err := skiplist.ForEachCtx(context.Background(), skiplist.ForMap(kv, kv.Values()),
+			func(k, v int) error { return fail },
+		)
+
+		it.Then(t).Should(
+			it.True(errors.Is(err, fail)),
+		)
+	})
+}
+
+func TestAdapters(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	set := skiplist.NewSet[int]()
+	for i := 0; i < 10; i++ {
+		kv.Put(i, i*i)
+		set.Add(i)
+	}
+
+	lt5 := func(k, v int) bool { return k < 5 }
+	even := func(k, v int) bool { return k%2 == 0 }
+	str := func(k, v int) string { return strconv.Itoa(v) }
+
+	t.Run("Pairs", func(t *testing.T) {
+		vals := []string{}
+		err := skiplist.ForEach(
+			skiplist.FMap(skiplist.Filter(skiplist.ForMap(kv, kv.Values()), even), str),
+			func(k int, v string) error { vals = append(vals, v); return nil },
+		)
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Seq(keysOf(skiplist.TakeWhile(skiplist.ForMap(kv, kv.Values()), lt5))).Equal(0, 1, 2, 3, 4),
+			it.Seq(keysOf(skiplist.DropWhile(skiplist.ForMap(kv, kv.Values()), lt5))).Equal(5, 6, 7, 8, 9),
+			it.Seq(vals).Equal("0", "4", "16", "36", "64"),
+		)
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		keys := []string{}
+		err := skiplist.ForEachKeys(
+			skiplist.FMapKeys(
+				skiplist.FilterKeys(skiplist.ForSet(set, set.Values()), func(k int) bool { return k%2 == 1 }),
+				strconv.Itoa,
+			),
+			func(k string) error { keys = append(keys, k); return nil },
+		)
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Seq(valuesOf(skiplist.TakeWhileKeys(skiplist.ForSet(set, set.Values()), func(k int) bool { return k < 3 }))).Equal(0, 1, 2),
+			it.Seq(valuesOf(skiplist.DropWhileKeys(skiplist.ForSet(set, set.Values()), func(k int) bool { return k < 7 }))).Equal(7, 8, 9),
+			it.Seq(keys).Equal("1", "3", "5", "7", "9"),
+		)
+	})
+}
+
+func TestErrStop(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	set := skiplist.NewSet[int]()
+	for i := 0; i < 10; i++ {
+		kv.Put(i, i)
+		set.Add(i)
+	}
+
+	visit := func(n *int) func(int) error {
+		return func(k int) error {
+			*n++
+			if k == 3 {
+				return skiplist.ErrStop
+			}
+			return nil
+		}
+	}
+
+	var a, b, c, d int
+	errA := skiplist.ForEach(skiplist.ForMap(kv, kv.Values()), func(k, v int) error { return visit(&a)(k) })
+	errB := skiplist.ForEachKeys(skiplist.ForSet(set, set.Values()), visit(&b))
+	errC := skiplist.ForEachCtx(context.Background(), skiplist.ForMap(kv, kv.Values()), func(k, v int) error { return visit(&c)(k) })
+	errD := skiplist.ForEachCtxKeys(context.Background(), skiplist.ForSet(set, set.Values()), visit(&d))
+
+	it.Then(t).Should(
+		it.Nil(errA), it.Equal(a, 4),
+		it.Nil(errB), it.Equal(b, 4),
+		it.Nil(errC), it.Equal(c, 4),
+		it.Nil(errD), it.Equal(d, 4),
+	)
+}
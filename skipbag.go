@@ -0,0 +1,116 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// Bag is ordered multiset, it counts occurrences of each key.
+type Bag[K any] struct {
+	kv     *Map[K, int]
+	length int
+}
+
+// NewBag creates instance of multiset
+func NewBag[K Key]() *Bag[K] {
+	return NewBagWith[K](ord.Type[K]{})
+}
+
+// NewBagWith creates instance of multiset, keys are ordered by
+// the given type class
+func NewBagWith[K any](cmp ord.Ord[K]) *Bag[K] {
+	return &Bag[K]{kv: NewMapWith[K, int](cmp)}
+}
+
+// Length returns number of occurrences
+func (bag *Bag[K]) Length() int {
+	return bag.length
+}
+
+// Unique returns number of unique keys
+func (bag *Bag[K]) Unique() int {
+	return bag.kv.Length()
+}
+
+// Add occurrence of the key, returns its multiplicity
+func (bag *Bag[K]) Add(key K) int {
+	_, el := bag.kv.getOrInsert(key, 0)
+	el.Value++
+	bag.length++
+
+	return el.Value
+}
+
+// Cut occurrence of the key, returns its remaining multiplicity.
+// The key is removed once multiplicity drops to zero.
+func (bag *Bag[K]) Cut(key K) int {
+	el := bag.kv.Find(key)
+	if el == nil {
+		return 0
+	}
+
+	bag.length--
+	if el.Value == 1 {
+		bag.kv.Cut(key)
+		return 0
+	}
+
+	el.Value--
+	return el.Value
+}
+
+// Count returns multiplicity of the key
+func (bag *Bag[K]) Count(key K) int {
+	n, _ := bag.kv.Get(key)
+	return n
+}
+
+// All unique keys with multiplicity in key order
+//
+//	for k, n := range bag.All() { /* ... */ }
+func (bag *Bag[K]) All() iter.Seq2[K, int] {
+	return func(yield func(K, int) bool) {
+		for e := bag.kv.Values(); e != nil; e = e.Next() {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys yields unique keys in order
+//
+//	for k := range bag.Keys() { /* ... */ }
+func (bag *Bag[K]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for e := bag.kv.Values(); e != nil; e = e.Next() {
+			if !yield(e.Key) {
+				return
+			}
+		}
+	}
+}
+
+// Occurrences yields each key as many times as it was added
+//
+//	for k := range bag.Occurrences() { /* ... */ }
+func (bag *Bag[K]) Occurrences() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for e := bag.kv.Values(); e != nil; e = e.Next() {
+			for i := 0; i < e.Value; i++ {
+				if !yield(e.Key) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestBag(t *testing.T) {
+	bag := skiplist.NewBag[string]()
+
+	it.Then(t).Should(
+		it.Equal(bag.Add("b"), 1),
+		it.Equal(bag.Add("a"), 1),
+		it.Equal(bag.Add("b"), 2),
+		it.Equal(bag.Add("c"), 1),
+		it.Equal(bag.Add("b"), 3),
+		it.Equal(bag.Length(), 5),
+		it.Equal(bag.Unique(), 3),
+		it.Equal(bag.Count("b"), 3),
+		it.Equal(bag.Count("d"), 0),
+		it.Seq(slices.Collect(bag.Keys())).Equal("a", "b", "c"),
+		it.Seq(slices.Collect(bag.Occurrences())).Equal("a", "b", "b", "b", "c"),
+	)
+
+	it.Then(t).Should(
+		it.Equal(bag.Cut("b"), 2),
+		it.Equal(bag.Cut("a"), 0),
+		it.Equal(bag.Cut("a"), 0),
+		it.Equal(bag.Length(), 3),
+		it.Equal(bag.Unique(), 2),
+		it.Seq(slices.Collect(bag.Occurrences())).Equal("b", "b", "c"),
+	)
+
+	counts := map[string]int{}
+	for k, n := range bag.All() {
+		counts[k] = n
+	}
+	it.Then(t).Should(
+		it.Equal(counts["b"], 2),
+		it.Equal(counts["c"], 1),
+	)
+}
@@ -0,0 +1,276 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// ConcurrentMap is lock-free skip list, fingers are linked with
+// compare-and-swap. It supports concurrent Put, Get and Cut, ordered reads
+// are wait-free. The implementation follows the lock-free skip list by
+// Fraser, Herlihy and Shavit: node is removed by marking its fingers
+// top-down, the marked node is physically unlinked by subsequent traversals.
+type ConcurrentMap[K any, V any] struct {
+	head    *cnode[K, V]
+	length  atomic.Int64
+	ptable  [L]float64
+	compare func(K, K) int
+}
+
+// finger of node, the finger is immutable and replaced as a whole with CAS.
+// The marked finger denotes logical removal of the node it belongs to.
+type cfinger[K any, V any] struct {
+	node   *cnode[K, V]
+	marked bool
+}
+
+type cnode[K any, V any] struct {
+	key     K
+	val     atomic.Pointer[V]
+	fingers []atomic.Pointer[cfinger[K, V]]
+}
+
+func newCNode[K any, V any](key K, rank int) *cnode[K, V] {
+	node := &cnode[K, V]{key: key, fingers: make([]atomic.Pointer[cfinger[K, V]], rank)}
+	for i := range node.fingers {
+		node.fingers[i].Store(&cfinger[K, V]{})
+	}
+	return node
+}
+
+// NewConcurrentMap creates instance of lock-free skip list
+func NewConcurrentMap[K Key, V any]() *ConcurrentMap[K, V] {
+	return NewConcurrentMapWith[K, V](ord.Type[K]{})
+}
+
+// NewConcurrentMapWith creates instance of lock-free skip list, keys are
+// ordered by the given type class
+func NewConcurrentMapWith[K any, V any](cmp ord.Ord[K]) *ConcurrentMap[K, V] {
+	return &ConcurrentMap[K, V]{
+		head:    newCNode[K, V](*new(K), L),
+		ptable:  probabilityTable,
+		compare: cmp.Compare,
+	}
+}
+
+// Length of the map, the value is approximate while writes are in progress
+func (kv *ConcurrentMap[K, V]) Length() int {
+	return int(kv.length.Load())
+}
+
+// find pointers to the rightmost nodes of each level that precede the key
+// and their successors, it unlinks marked nodes on the way.
+func (kv *ConcurrentMap[K, V]) find(key K, path, next *[L]*cnode[K, V]) bool {
+retry:
+	for {
+		var curr *cnode[K, V]
+
+		node := kv.head
+		for lev := L - 1; lev >= 0; lev-- {
+			prev := node.fingers[lev].Load()
+			if prev.marked {
+				continue retry
+			}
+
+			curr = prev.node
+			for curr != nil {
+				succ := curr.fingers[lev].Load()
+				for succ.marked {
+					snip := &cfinger[K, V]{node: succ.node}
+					if !node.fingers[lev].CompareAndSwap(prev, snip) {
+						continue retry
+					}
+
+					prev, curr = snip, succ.node
+					if curr == nil {
+						break
+					}
+					succ = curr.fingers[lev].Load()
+				}
+
+				if curr == nil || kv.compare(curr.key, key) >= 0 {
+					break
+				}
+
+				node, prev, curr = curr, succ, succ.node
+			}
+
+			path[lev], next[lev] = node, curr
+		}
+
+		return curr != nil && kv.compare(curr.key, key) == 0
+	}
+}
+
+func (kv *ConcurrentMap[K, V]) level() int {
+	// See: https://golang.org/src/math/rand/rand.go#L150
+	p := float64(rand.Int63()) / (1 << 63)
+
+	level := 0
+	for level < L && p < kv.ptable[level] {
+		level++
+	}
+
+	return level
+}
+
+// Put key-value pair into map, returns true if pair is new
+func (kv *ConcurrentMap[K, V]) Put(key K, val V) bool {
+	var path, next [L]*cnode[K, V]
+
+	rank := kv.level()
+	for {
+		if kv.find(key, &path, &next) {
+			next[0].val.Store(&val)
+			return false
+		}
+
+		node := newCNode[K, V](key, rank)
+		node.val.Store(&val)
+		for lev := 0; lev < rank; lev++ {
+			node.fingers[lev].Store(&cfinger[K, V]{node: next[lev]})
+		}
+
+		prev := path[0].fingers[0].Load()
+		if prev.marked || prev.node != next[0] {
+			continue
+		}
+		if !path[0].fingers[0].CompareAndSwap(prev, &cfinger[K, V]{node: node}) {
+			continue
+		}
+
+		kv.length.Add(1)
+		kv.link(node, rank, &path, &next)
+		return true
+	}
+}
+
+// link upper fingers of node that is already linked on level 0
+func (kv *ConcurrentMap[K, V]) link(node *cnode[K, V], rank int, path, next *[L]*cnode[K, V]) {
+	for lev := 1; lev < rank; lev++ {
+		for {
+			prev := path[lev].fingers[lev].Load()
+			if !prev.marked && prev.node == next[lev] &&
+				path[lev].fingers[lev].CompareAndSwap(prev, &cfinger[K, V]{node: node}) {
+				break
+			}
+
+			kv.find(node.key, path, next)
+
+			succ := node.fingers[lev].Load()
+			if succ.marked {
+				return
+			}
+			if succ.node != next[lev] {
+				node.fingers[lev].CompareAndSwap(succ, &cfinger[K, V]{node: next[lev]})
+			}
+		}
+	}
+}
+
+// Get value of the key, the operation is wait-free
+func (kv *ConcurrentMap[K, V]) Get(key K) (V, bool) {
+	node := kv.head
+	var curr *cnode[K, V]
+
+	for lev := L - 1; lev >= 0; lev-- {
+		curr = node.fingers[lev].Load().node
+		for curr != nil {
+			succ := curr.fingers[lev].Load()
+			if succ.marked {
+				curr = succ.node
+				continue
+			}
+
+			if kv.compare(curr.key, key) >= 0 {
+				break
+			}
+
+			node, curr = curr, succ.node
+		}
+	}
+
+	if curr == nil || kv.compare(curr.key, key) != 0 || curr.fingers[0].Load().marked {
+		return *new(V), false
+	}
+
+	return *curr.val.Load(), true
+}
+
+// Cut key from the map, returns removed value and true if key existed
+func (kv *ConcurrentMap[K, V]) Cut(key K) (V, bool) {
+	var path, next [L]*cnode[K, V]
+
+	if !kv.find(key, &path, &next) {
+		return *new(V), false
+	}
+
+	node := next[0]
+	for lev := len(node.fingers) - 1; lev > 0; lev-- {
+		for {
+			succ := node.fingers[lev].Load()
+			if succ.marked ||
+				node.fingers[lev].CompareAndSwap(succ, &cfinger[K, V]{node: succ.node, marked: true}) {
+				break
+			}
+		}
+	}
+
+	for {
+		succ := node.fingers[0].Load()
+		if succ.marked {
+			// concurrent Cut has removed the node
+			return *new(V), false
+		}
+
+		if node.fingers[0].CompareAndSwap(succ, &cfinger[K, V]{node: succ.node, marked: true}) {
+			kv.length.Add(-1)
+			kv.find(key, &path, &next)
+			return *node.val.Load(), true
+		}
+	}
+}
+
+// All pairs of the map in key order, the iteration is wait-free and
+// observes concurrent writes.
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *ConcurrentMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		kv.ascend(kv.head.fingers[0].Load().node)(yield)
+	}
+}
+
+// Ascend iterates over pairs of the map starting from the key
+//
+//	for k, v := range kv.Ascend(key) { /* ... */ }
+func (kv *ConcurrentMap[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var path, next [L]*cnode[K, V]
+		kv.find(from, &path, &next)
+		kv.ascend(next[0])(yield)
+	}
+}
+
+func (kv *ConcurrentMap[K, V]) ascend(el *cnode[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := el; e != nil; {
+			succ := e.fingers[0].Load()
+			if !succ.marked && !yield(e.key, *e.val.Load()) {
+				return
+			}
+			e = succ.node
+		}
+	}
+}
@@ -0,0 +1,80 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestConcurrentMap(t *testing.T) {
+	const n = 2000
+	kv := skiplist.NewConcurrentMap[int, int]()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += 8 {
+				kv.Put(i, i)
+				kv.Put(n-i-1, n-i-1)
+				if v, has := kv.Get(i); has {
+					it.Then(t).Should(it.Equal(v, i))
+				}
+				if i%3 == 0 {
+					kv.Cut(i)
+				}
+				for k, v := range kv.Ascend(i) {
+					it.Then(t).Should(it.Equal(k, v))
+					break
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	keys := []int{}
+	for k, v := range kv.All() {
+		it.Then(t).Should(it.Equal(k, v))
+		keys = append(keys, k)
+	}
+
+	for i := 0; i < n; i++ {
+		_, has := kv.Get(i)
+		if i%3 != 0 {
+			it.Then(t).Should(it.True(has))
+		}
+	}
+
+	it.Then(t).Should(
+		it.True(sort.IntsAreSorted(keys)),
+		it.Equal(len(keys), kv.Length()),
+		it.True(kv.Put(-1, -1)),
+		it.Equal(kv.Length(), len(keys)+1),
+	).ShouldNot(
+		it.True(kv.Put(-1, -1)),
+	)
+
+	for _, k := range keys {
+		_, has := kv.Cut(k)
+		it.Then(t).Should(it.True(has))
+	}
+
+	val, has := kv.Cut(-1)
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, -1),
+		it.Equal(kv.Length(), 0),
+	)
+}
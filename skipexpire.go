@@ -0,0 +1,141 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"time"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// ExpireMap is ordered map with entries expiring at deadline. Get and
+// iteration lazily skip expired entries, Expire removes them in order of
+// deadline using secondary expiry index. The map is not thread safe.
+type ExpireMap[K any, V any] struct {
+	kv     *Map[K, expiring[V]]
+	expiry *Map[ekey[K], struct{}]
+}
+
+// value of the map with its deadline
+type expiring[V any] struct {
+	value    V
+	deadline time.Time
+}
+
+// key of the expiry index
+type ekey[K any] struct {
+	deadline time.Time
+	key      K
+}
+
+// NewExpireMap creates instance of map with expiring entries
+func NewExpireMap[K Key, V any]() *ExpireMap[K, V] {
+	return NewExpireMapWith[K, V](ord.Type[K]{})
+}
+
+// NewExpireMapWith creates instance of map with expiring entries, keys are
+// ordered by the given type class
+func NewExpireMapWith[K any, V any](cmp ord.Ord[K]) *ExpireMap[K, V] {
+	compare := func(a, b ekey[K]) int {
+		if c := a.deadline.Compare(b.deadline); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.key, b.key)
+	}
+
+	return &ExpireMap[K, V]{
+		kv:     NewMapWith[K, expiring[V]](cmp),
+		expiry: NewMapFunc[ekey[K], struct{}](compare),
+	}
+}
+
+// Length returns number of entries, including expired entries not removed yet
+func (kv *ExpireMap[K, V]) Length() int {
+	return kv.kv.Length()
+}
+
+// Put value of the key with the deadline, returns true if key is new
+func (kv *ExpireMap[K, V]) Put(key K, val V, deadline time.Time) bool {
+	isNew, el := kv.kv.getOrInsert(key, expiring[V]{value: val, deadline: deadline})
+	if !isNew {
+		kv.expiry.Cut(ekey[K]{deadline: el.Value.deadline, key: key})
+		el.Value = expiring[V]{value: val, deadline: deadline}
+	}
+
+	kv.expiry.Put(ekey[K]{deadline: deadline, key: key}, struct{}{})
+
+	return isNew
+}
+
+// Get value of the key, expired entries are not visible
+func (kv *ExpireMap[K, V]) Get(key K) (V, bool) {
+	val, has := kv.kv.Get(key)
+	if !has || !time.Now().Before(val.deadline) {
+		return *new(V), false
+	}
+
+	return val.value, true
+}
+
+// Deadline of the key
+func (kv *ExpireMap[K, V]) Deadline(key K) (time.Time, bool) {
+	val, has := kv.kv.Get(key)
+	if !has {
+		return time.Time{}, false
+	}
+
+	return val.deadline, true
+}
+
+// Cut key from the map, returns the value and true if key is removed.
+// The value of expired key is not returned.
+func (kv *ExpireMap[K, V]) Cut(key K) (V, bool) {
+	val, has := kv.kv.Cut(key)
+	if !has {
+		return *new(V), false
+	}
+
+	kv.expiry.Cut(ekey[K]{deadline: val.deadline, key: key})
+	if !time.Now().Before(val.deadline) {
+		return *new(V), false
+	}
+
+	return val.value, true
+}
+
+// Expire removes entries with deadline at or before now,
+// returns number of removed entries
+func (kv *ExpireMap[K, V]) Expire(now time.Time) int {
+	n := 0
+	for e := kv.expiry.Values(); e != nil && !now.Before(e.Key.deadline); e = kv.expiry.Values() {
+		kv.expiry.Cut(e.Key)
+		kv.kv.Cut(e.Key.key)
+		n++
+	}
+
+	return n
+}
+
+// All live pairs in key order
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *ExpireMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		now := time.Now()
+		for e := kv.kv.Values(); e != nil; e = e.Next() {
+			if now.Before(e.Value.deadline) {
+				if !yield(e.Key, e.Value.value) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestExpireMap(t *testing.T) {
+	now := time.Now()
+	kv := skiplist.NewExpireMap[string, int]()
+
+	it.Then(t).Should(
+		it.Equal(kv.Put("a", 1, now.Add(time.Hour)), true),
+		it.Equal(kv.Put("b", 2, now.Add(-time.Second)), true),
+		it.Equal(kv.Put("c", 3, now.Add(2*time.Hour)), true),
+		it.Equal(kv.Put("d", 4, now.Add(3*time.Hour)), true),
+		it.Equal(kv.Put("c", 5, now.Add(-time.Minute)), false),
+		it.Equal(kv.Length(), 4),
+	)
+
+	val, has := kv.Get("a")
+	it.Then(t).Should(it.True(has), it.Equal(val, 1))
+
+	_, has = kv.Get("b")
+	it.Then(t).Should(it.True(!has))
+
+	deadline, has := kv.Deadline("c")
+	it.Then(t).Should(it.True(has), it.Equal(deadline, now.Add(-time.Minute)))
+
+	keys := []string{}
+	for k := range kv.All() {
+		keys = append(keys, k)
+	}
+	it.Then(t).Should(it.Seq(keys).Equal("a", "d"))
+
+	it.Then(t).Should(
+		it.Equal(kv.Expire(now), 2),
+		it.Equal(kv.Length(), 2),
+		it.Equal(kv.Expire(now.Add(90*time.Minute)), 1),
+		it.Equal(kv.Length(), 1),
+	)
+
+	val, has = kv.Cut("d")
+	_, had := kv.Cut("d")
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, 4),
+		it.True(!had),
+		it.Equal(kv.Expire(now.Add(24*time.Hour)), 0),
+	)
+}
@@ -9,71 +9,136 @@
 package skiplist
 
 import (
+	"errors"
 	"fmt"
+	"iter"
+	"math/bits"
 	"reflect"
 	"strings"
+	"unsafe"
 )
 
+// ErrNonContinuous is returned when arcs do not cover the field continuously
+var ErrNonContinuous = errors.New("skiplist: non-continuous field")
+
+// ErrInvalidRank is returned when rank of arc exceeds log2 of its size
+var ErrInvalidRank = errors.New("skiplist: rank of arc exceeds its size")
+
 type Num interface {
 	~uint8 | ~uint16 | ~uint32 | ~uint64
 }
 
+// Signed integer types, GF2 partitions them through FromSigned adapter
+type Signed interface {
+	~int8 | ~int16 | ~int32 | ~int64
+}
+
+// FromSigned maps signed key into the unsigned key of the field of the same
+// width (e.g. int32 into uint32), the mapping preserves order: the smallest
+// signed value maps to 0, the largest one to the top of field. It panics if
+// widths of types differ, a narrower field would truncate keys and a wider
+// one would crowd keys in the middle of the field.
+//
+//	arc, _ := gf2.Get(skiplist.FromSigned[uint32](int32(-10)))
+func FromSigned[U Num, S Signed](key S) U {
+	w := signedWidth[U, S]()
+	return U(uint64(int64(key)) ^ (1 << (w - 1)))
+}
+
+// ToSigned is inverse of FromSigned, it maps the key of the field (e.g. arc
+// bounds) back to signed type of the same width.
+func ToSigned[S Signed, U Num](key U) S {
+	w := signedWidth[U, S]()
+	x := uint64(key) ^ (1 << (w - 1))
+
+	return S(int64(x<<(64-w)) >> (64 - w))
+}
+
+// width of paired signed and unsigned types
+func signedWidth[U Num, S Signed]() uintptr {
+	var (
+		u U
+		s S
+	)
+
+	if unsafe.Sizeof(u) != unsafe.Sizeof(s) {
+		panic(fmt.Sprintf("skiplist: width of %T differs from %T", u, s))
+	}
+
+	return unsafe.Sizeof(u) * 8
+}
+
+// FromUint128 maps 128-bit key (e.g. hash or UUID) into the key of the field
+// using its most significant bits. The field partitions 128-bit space at the
+// granularity of 2¹²⁸⁻ⁿ keys, where n is width of the field.
+//
+//	arc, _ := gf2.Get(skiplist.FromUint128[uint64](hi, lo))
+func FromUint128[U Num](hi, lo uint64) U {
+	var u U
+	w := unsafe.Sizeof(u) * 8
+
+	return U(hi >> (64 - w))
+}
+
+// GF2 is a field of arcs, each arc covers interval of keys [Lo, Hi].
+// Arcs are stored in skip map by the upper bound of the interval.
 type GF2[K Num] struct {
-	keys *Set[K]
-	arcs map[K]Arc[K]
+	arcs *Map[K, Arc[K]]
 }
 
 type Arc[K Num] struct {
 	Rank   uint32
 	Lo, Hi K
+
+	// load of the arc, it is accumulated by Hit and shared between
+	// halves of split arc
+	Load uint64
 }
 
 func (arc Arc[K]) String() string {
 	return fmt.Sprintf("{ %2d : %8x - %8x | %10d - %10d }", arc.Rank, arc.Lo, arc.Hi, arc.Lo, arc.Hi)
 }
 
-func NewGF2[K Num](opts ...SetConfig[K]) *GF2[K] {
-	keys := NewSet(opts...)
+// NewGF2 creates field covered by single arc, the options configure the map
+// of arcs (e.g. probability, levels or allocator). The field relies on
+// ascending order of all arcs, it panics if options define descending order,
+// capacity or hooks of the map.
+func NewGF2[K Num](opts ...MapConfig[K, Arc[K]]) *GF2[K] {
+	arcs := NewMap(opts...)
+	if arcs.desc || arcs.bound != nil || arcs.onPut != nil || arcs.onCut != nil {
+		panic("unsupported configuration of field")
+	}
 
 	top := *new(K) - 1
-	keys.Add(top)
 	rnk := uint32(reflect.TypeOf(top).Size() * 8)
+	arcs.Put(top, Arc[K]{Rank: rnk, Lo: 0, Hi: top})
 
-	arcs := map[K]Arc[K]{
-		top: {Rank: rnk, Lo: 0, Hi: top},
-	}
-
-	return &GF2[K]{
-		keys: keys,
-		arcs: arcs,
-	}
+	return &GF2[K]{arcs: arcs}
 }
 
 func (f *GF2[K]) String() string {
 	sb := strings.Builder{}
 	sb.WriteString(fmt.Sprintf("--- SkipGF2[%T] %p ---\n", *new(K), &f))
 
-	for node := f.keys.Values(); node != nil; node = node.Next() {
-		key := node.Key
-		arc := f.arcs[key]
-		sb.WriteString(arc.String())
+	for node := f.arcs.Values(); node != nil; node = node.Next() {
+		sb.WriteString(node.Value.String())
 		sb.WriteString("\n")
 	}
 
 	return sb.String()
 }
 
-func (f *GF2[K]) Length() int { return f.keys.length }
+func (f *GF2[K]) Length() int { return f.arcs.length }
 
 // Add new element to the field
 func (f *GF2[K]) Add(key K) (Arc[K], Arc[K]) {
-	node := f.keys.Successor(key)
+	node := f.arcs.Successor(key)
 	if node == nil {
 		panic("non-continuos field")
 	}
 
 	hi := node.Key
-	tail := f.arcs[hi]
+	tail := node.Value
 
 	if tail.Rank == 0 {
 		return tail, tail
@@ -82,39 +147,313 @@ func (f *GF2[K]) Add(key K) (Arc[K], Arc[K]) {
 	rnk := tail.Rank - 1
 	mid := tail.Lo + (hi-tail.Lo)/2
 
-	head := Arc[K]{Rank: rnk, Lo: tail.Lo, Hi: mid}
-	tail.Rank, tail.Lo = rnk, mid+1
+	head := Arc[K]{Rank: rnk, Lo: tail.Lo, Hi: mid, Load: tail.Load / 2}
+	tail.Rank, tail.Lo, tail.Load = rnk, mid+1, tail.Load-head.Load
 
-	f.keys.Add(mid)
-	f.arcs[mid] = head
-	f.arcs[hi] = tail
+	node.Value = tail
+	f.arcs.Put(mid, head)
 
 	return head, tail
 }
 
-// Put element
-func (f *GF2[K]) Put(arc Arc[K]) bool {
-	added, _ := f.keys.Add(arc.Hi)
+// Hit adds load to the arc covering the key, returns the arc
+func (f *GF2[K]) Hit(key K, load uint64) Arc[K] {
+	node := f.arcs.Successor(key)
+	if node == nil {
+		panic("non-continuos field")
+	}
+
+	node.Value.Load += load
+	return node.Value
+}
+
+// ResetLoad sets load of all arcs to zero
+func (f *GF2[K]) ResetLoad() {
+	for node := f.arcs.Values(); node != nil; node = node.Next() {
+		node.Value.Load = 0
+	}
+}
+
+// SplitHottest splits the arc with the highest load in halves (see Add).
+// The arc is not split if no arc can be divided further.
+func (f *GF2[K]) SplitHottest() (Arc[K], Arc[K]) {
+	return f.splitMax(func(a, b Arc[K]) bool { return a.Load > b.Load })
+}
+
+// SplitLargest splits the widest arc in halves (see Add).
+// The arc is not split if no arc can be divided further.
+func (f *GF2[K]) SplitLargest() (Arc[K], Arc[K]) {
+	return f.splitMax(func(a, b Arc[K]) bool { return a.Hi-a.Lo > b.Hi-b.Lo })
+}
 
-	f.arcs[arc.Hi] = arc
+// split the arc that is greater than others
+func (f *GF2[K]) splitMax(greater func(a, b Arc[K]) bool) (Arc[K], Arc[K]) {
+	var top *Pair[K, Arc[K]]
+
+	for node := f.arcs.Values(); node != nil; node = node.Next() {
+		if node.Value.Rank > 0 && (top == nil || greater(node.Value, top.Value)) {
+			top = node
+		}
+	}
+
+	if top == nil {
+		arc := f.arcs.Values().Value
+		return arc, arc
+	}
 
-	return added
+	return f.Add(top.Key)
+}
+
+// SplitAt splits the arc of the key at the given boundary, the head arc
+// covers [Lo, at] and the tail arc covers [at+1, Hi]. The rank of each arc
+// is floor(log2) of its size, it is equal to rank of Add if the boundary is
+// the midpoint. The arc is not split if boundary is outside of [Lo, Hi).
+func (f *GF2[K]) SplitAt(key K, at K) (Arc[K], Arc[K]) {
+	node := f.arcs.Successor(key)
+	if node == nil {
+		panic("non-continuos field")
+	}
+
+	tail := node.Value
+	if at < tail.Lo || at >= tail.Hi {
+		return tail, tail
+	}
+
+	// load is shared in proportion to size of arcs
+	load := uint64(float64(tail.Load) * (float64(at-tail.Lo) + 1) / (float64(tail.Hi-tail.Lo) + 1))
+
+	head := Arc[K]{Rank: rankOf(tail.Lo, at), Lo: tail.Lo, Hi: at, Load: load}
+	tail.Rank, tail.Lo, tail.Load = rankOf(at+1, tail.Hi), at+1, tail.Load-load
+
+	node.Value = tail
+	f.arcs.Put(at, head)
+
+	return head, tail
+}
+
+// rank of interval [lo, hi], the interval is not entire field
+func rankOf[K Num](lo, hi K) uint32 {
+	return uint32(bits.Len64(uint64(hi-lo)+1) - 1)
+}
+
+// Merge is inverse of Add, it joins the arc of the key with its sibling
+// back into the parent arc. The sibling is the neighbor arc of the same rank
+// that forms aligned parent interval. It returns false if sibling is
+// split further, the arc covers entire field or the arc is not aligned
+// interval of 2^Rank keys (e.g. produced by SplitAt or Cut).
+func (f *GF2[K]) Merge(key K) (Arc[K], bool) {
+	node := f.arcs.Successor(key)
+	if node == nil {
+		panic("non-continuos field")
+	}
+
+	arc := node.Value
+	if arc.Rank >= uint32(reflect.TypeOf(key).Size()*8) {
+		return arc, false
+	}
+
+	size := arc.Hi - arc.Lo + 1
+	if size != K(1)<<arc.Rank || arc.Lo&(size-1) != 0 {
+		return arc, false
+	}
+
+	// the arc is lower half of parent, the sibling follows the arc
+	if arc.Lo&size == 0 {
+		next := node.Next()
+		if next == nil || next.Value.Rank != arc.Rank || next.Value.Lo != arc.Hi+1 || next.Value.Hi-next.Value.Lo+1 != size {
+			return arc, false
+		}
+
+		next.Value.Lo, next.Value.Rank = arc.Lo, arc.Rank+1
+		next.Value.Load += arc.Load
+		f.arcs.Cut(arc.Hi)
+		return next.Value, true
+	}
+
+	// the arc is upper half of parent, the sibling precedes the arc
+	prev, has := f.arcs.Get(arc.Lo - 1)
+	if !has || prev.Rank != arc.Rank || prev.Lo != arc.Lo-size || prev.Hi != arc.Lo-1 {
+		return arc, false
+	}
+
+	node.Value.Lo, node.Value.Rank = prev.Lo, arc.Rank+1
+	node.Value.Load += prev.Load
+	f.arcs.Cut(prev.Hi)
+	return node.Value, true
+}
+
+// Cut removes the arc of the key and donates its interval to the neighbor,
+// the field remains continuous. The interval goes to the following arc,
+// the last arc donates to the preceding one. Siblings are merged into
+// parent arc (see Merge), otherwise the neighbor retains its rank. It returns
+// the neighbor and false if the arc covers entire field.
+func (f *GF2[K]) Cut(key K) (Arc[K], bool) {
+	node := f.arcs.Successor(key)
+	if node == nil {
+		panic("non-continuos field")
+	}
+
+	arc := node.Value
+	if f.arcs.length == 1 {
+		return arc, false
+	}
+
+	if parent, ok := f.Merge(key); ok {
+		return parent, true
+	}
+
+	if next := node.Next(); next != nil {
+		next.Value.Lo = arc.Lo
+		next.Value.Load += arc.Load
+		f.arcs.Cut(arc.Hi)
+		return next.Value, true
+	}
+
+	prev, _ := f.arcs.Get(arc.Lo - 1)
+	node.Value = Arc[K]{Rank: prev.Rank, Lo: prev.Lo, Hi: arc.Hi, Load: prev.Load + arc.Load}
+	f.arcs.Cut(prev.Hi)
+	return node.Value, true
+}
+
+// Put element
+func (f *GF2[K]) Put(arc Arc[K]) bool {
+	return f.arcs.Put(arc.Hi, arc)
 }
 
 // Check elements position on the field
 func (f *GF2[K]) Get(key K) (Arc[K], bool) {
-	node := f.keys.Successor(key)
+	node := f.arcs.Successor(key)
 	if node == nil {
 		panic("non-continuos field")
 	}
 
-	return f.arcs[node.Key], true
+	return node.Value, true
+}
+
+// Predecessor arc of the arc covering the key. The field is a ring, the
+// predecessor of the first arc is the last one.
+func (f *GF2[K]) Predecessor(key K) Arc[K] {
+	prev, _ := f.Neighbors(key)
+	return prev
 }
 
-func (f *GF2[K]) Keys() *Element[K] {
-	return f.keys.Values()
+// Neighbors returns arcs immediately before and after the arc covering
+// the key. The field is a ring, the first and last arcs are neighbors.
+func (f *GF2[K]) Neighbors(key K) (Arc[K], Arc[K]) {
+	node := f.arcs.Successor(key)
+	if node == nil {
+		panic("non-continuos field")
+	}
+
+	next := node.Next()
+	if next == nil {
+		next = f.arcs.Values()
+	}
+
+	top := *new(K) - 1
+	prev := f.arcs.Successor(node.Value.Lo - 1)
+	if node.Value.Lo == 0 {
+		prev = f.arcs.Successor(top)
+	}
+
+	return prev.Value, next.Value
+}
+
+// Keys of the field, the value of each key is the arc
+func (f *GF2[K]) Keys() *Pair[K, Arc[K]] {
+	return f.arcs.Values()
+}
+
+// Successor arc of the key
+func (f *GF2[K]) Successor(key K) *Pair[K, Arc[K]] {
+	return f.arcs.Successor(key)
+}
+
+// All arcs of the field in order
+//
+//	for hi, arc := range f.All() { /* ... */ }
+func (f *GF2[K]) All() iter.Seq2[K, Arc[K]] {
+	return f.arcs.All()
+}
+
+// Covering arcs that intersect the interval [lo, hi] in order
+//
+//	for hi, arc := range f.Covering(lo, hi) { /* ... */ }
+func (f *GF2[K]) Covering(lo, hi K) iter.Seq2[K, Arc[K]] {
+	return func(yield func(K, Arc[K]) bool) {
+		if lo > hi {
+			return
+		}
+
+		for node := f.arcs.Successor(lo); node != nil && node.Value.Lo <= hi; node = node.Next() {
+			if !yield(node.Key, node.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Export topology of the field, arcs are listed in order
+func (f *GF2[K]) Export() []Arc[K] {
+	arcs := make([]Arc[K], 0, f.arcs.length)
+	for node := f.arcs.Values(); node != nil; node = node.Next() {
+		arcs = append(arcs, node.Value)
+	}
+
+	return arcs
+}
+
+// Import topology of the field, it replaces arcs of the field. Arcs must be
+// sorted and continuously cover the field, otherwise ErrNonContinuous is
+// returned. The rank of arc must not exceed log2 of its size, otherwise
+// ErrInvalidRank is returned. The field remains unchanged on error.
+func (f *GF2[K]) Import(arcs []Arc[K]) error {
+	kv := NewMap[K, Arc[K]]()
+	if f.arcs != nil {
+		kv = f.arcs.Clone()
+		kv.Clear()
+	}
+
+	return f.restore(kv, arcs)
+}
+
+// restore arcs into empty map, the map replaces arcs of the field
+func (f *GF2[K]) restore(kv *Map[K, Arc[K]], arcs []Arc[K]) error {
+	top := *new(K) - 1
+	width := uint32(reflect.TypeOf(top).Size() * 8)
+
+	if len(arcs) == 0 || arcs[0].Lo != 0 || arcs[len(arcs)-1].Hi != top {
+		return ErrNonContinuous
+	}
+
+	for i, arc := range arcs {
+		// the previous arc ending at top would wrap around the field
+		if arc.Lo > arc.Hi || (i > 0 && (arcs[i-1].Hi == top || arcs[i-1].Hi+1 != arc.Lo)) {
+			return ErrNonContinuous
+		}
+
+		rank := width
+		if arc.Lo != 0 || arc.Hi != top {
+			rank = rankOf(arc.Lo, arc.Hi)
+		}
+
+		if arc.Rank > rank {
+			return ErrInvalidRank
+		}
+	}
+
+	b := newMapBuilder(kv)
+	for _, arc := range arcs {
+		if !b.append(arc.Hi, arc) {
+			return ErrNonContinuous
+		}
+	}
+
+	f.arcs = kv
+	return nil
 }
 
-func (f *GF2[K]) Successor(key K) *Element[K] {
-	return f.keys.Successor(key)
+// Clone makes a copy of the field
+func (f *GF2[K]) Clone() *GF2[K] {
+	return &GF2[K]{arcs: f.arcs.Clone()}
 }
@@ -9,6 +9,7 @@
 package skiplist_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/fogfish/it/v2"
@@ -86,6 +87,31 @@ func TestFieldPut(t *testing.T) {
 	)
 }
 
+func TestFieldClone(t *testing.T) {
+	gf2 := skiplist.NewGF2[uint8]()
+	gf2.Add(0x39)
+
+	clone := gf2.Clone()
+	clone.Add(0x39)
+
+	it.Then(t).Should(
+		it.Equal(gf2.Length(), 2),
+		it.Equal(clone.Length(), 3),
+	)
+
+	arc, _ := gf2.Get(0x39)
+	it.Then(t).Should(
+		it.Equal(arc.Lo, 0x00),
+		it.Equal(arc.Hi, 0x7f),
+	)
+
+	arc, _ = clone.Get(0x39)
+	it.Then(t).Should(
+		it.Equal(arc.Lo, 0x00),
+		it.Equal(arc.Hi, 0x3f),
+	)
+}
+
 // go test -fuzz=FuzzGF2
 func FuzzGF2(f *testing.F) {
 	field := skiplist.NewGF2[uint32]()
@@ -98,3 +124,361 @@ func FuzzGF2(f *testing.F) {
 		}
 	})
 }
+
+func TestFieldAll(t *testing.T) {
+	gf2 := skiplist.NewGF2[uint8]()
+	gf2.Add(0x39)
+	gf2.Add(0x39)
+
+	arcs := []skiplist.Arc[uint8]{}
+	for hi, arc := range gf2.All() {
+		it.Then(t).Should(it.Equal(hi, arc.Hi))
+		arcs = append(arcs, arc)
+	}
+
+	it.Then(t).Should(
+		it.Equal(len(arcs), 3),
+		it.Equal(arcs[0].Lo, 0x00),
+		it.Equal(arcs[1].Lo, 0x40),
+		it.Equal(arcs[2].Lo, 0x80),
+		it.Equal(gf2.Successor(0x50).Value.Hi, 0x7f),
+	)
+}
+
+func TestFieldMerge(t *testing.T) {
+	gf2 := skiplist.NewGF2[uint8]()
+	gf2.Add(0x39)
+	gf2.Add(0x39)
+	gf2.Add(0x39)
+
+	t.Run("Sibling", func(t *testing.T) {
+		_, ok := gf2.Merge(0x50)
+		it.Then(t).Should(it.True(!ok))
+
+		arc, ok := gf2.Merge(0x30)
+		it.Then(t).Should(
+			it.True(ok),
+			it.Equal(arc.Lo, 0x00),
+			it.Equal(arc.Hi, 0x3f),
+			it.Equal(arc.Rank, 6),
+			it.Equal(gf2.Length(), 3),
+		)
+	})
+
+	t.Run("Upper", func(t *testing.T) {
+		arc, ok := gf2.Merge(0x50)
+		it.Then(t).Should(
+			it.True(ok),
+			it.Equal(arc.Lo, 0x00),
+			it.Equal(arc.Hi, 0x7f),
+		)
+
+		arc, ok = gf2.Merge(0xf0)
+		it.Then(t).Should(
+			it.True(ok),
+			it.Equal(arc.Lo, 0x00),
+			it.Equal(arc.Hi, 0xff),
+			it.Equal(gf2.Length(), 1),
+		)
+
+		_, ok = gf2.Merge(0x00)
+		it.Then(t).Should(it.True(!ok))
+	})
+}
+
+func TestFieldCut(t *testing.T) {
+	gf2 := skiplist.NewGF2[uint8]()
+	gf2.Add(0x39)
+	gf2.Add(0x39)
+
+	// [00-3f] [40-7f] [80-ff]
+	arc, ok := gf2.Cut(0x50)
+	it.Then(t).Should(
+		it.True(ok),
+		it.Equal(arc.Lo, 0x00),
+		it.Equal(arc.Hi, 0x7f),
+		it.Equal(gf2.Length(), 2),
+	)
+
+	gf2.Add(0x10)
+	gf2.Add(0x10)
+
+	// [00-1f] [20-3f] [40-7f] [80-ff]
+	arc, ok = gf2.Cut(0x45)
+	it.Then(t).Should(
+		it.True(ok),
+		it.Equal(arc.Lo, 0x40),
+		it.Equal(arc.Hi, 0xff),
+	)
+
+	arc, ok = gf2.Cut(0xff)
+	it.Then(t).Should(
+		it.True(ok),
+		it.Equal(arc.Lo, 0x20),
+		it.Equal(arc.Hi, 0xff),
+		it.Equal(gf2.Length(), 2),
+	)
+
+	x, _ := gf2.Get(0x90)
+	it.Then(t).Should(it.Equal(x, arc))
+
+	gf2.Cut(0x00)
+	_, ok = gf2.Cut(0x00)
+	it.Then(t).Should(
+		it.True(!ok),
+		it.Equal(gf2.Length(), 1),
+	)
+}
+
+func TestFieldSplitAt(t *testing.T) {
+	gf2 := skiplist.NewGF2[uint8]()
+
+	hd, tl := gf2.SplitAt(0x10, 0x0f)
+	it.Then(t).Should(
+		it.Equal(hd, skiplist.Arc[uint8]{Rank: 4, Lo: 0x00, Hi: 0x0f}),
+		it.Equal(tl, skiplist.Arc[uint8]{Rank: 7, Lo: 0x10, Hi: 0xff}),
+	)
+
+	hd, tl = gf2.SplitAt(0x10, 0x7f)
+	it.Then(t).Should(
+		it.Equal(hd, skiplist.Arc[uint8]{Rank: 6, Lo: 0x10, Hi: 0x7f}),
+		it.Equal(tl, skiplist.Arc[uint8]{Rank: 7, Lo: 0x80, Hi: 0xff}),
+		it.Equal(gf2.Length(), 3),
+	)
+
+	hd, tl = gf2.SplitAt(0x90, 0xff)
+	it.Then(t).Should(
+		it.Equal(hd, tl),
+		it.Equal(gf2.Length(), 3),
+	)
+
+	arc, _ := gf2.Get(0x50)
+	it.Then(t).Should(it.Equal(arc.Lo, 0x10))
+
+	t.Run("Unaligned", func(t *testing.T) {
+		gf2 := skiplist.NewGF2[uint8]()
+		gf2.SplitAt(0x00, 0x00)
+		gf2.SplitAt(0x01, 0x02)
+		gf2.SplitAt(0x03, 0x04)
+
+		arc, ok := gf2.Merge(0x01)
+		it.Then(t).Should(
+			it.True(!ok),
+			it.Equal(arc, skiplist.Arc[uint8]{Rank: 1, Lo: 0x01, Hi: 0x02}),
+			it.Equal(gf2.Length(), 4),
+		)
+	})
+}
+
+func TestFieldCovering(t *testing.T) {
+	gf2 := skiplist.NewGF2[uint8]()
+	gf2.Add(0x39)
+	gf2.Add(0x39)
+
+	// [00-3f] [40-7f] [80-ff]
+	for _, x := range []struct {
+		lo, hi uint8
+		arcs   []uint8
+	}{
+		{0x00, 0x00, []uint8{0x3f}},
+		{0x3f, 0x40, []uint8{0x3f, 0x7f}},
+		{0x41, 0x7f, []uint8{0x7f}},
+		{0x10, 0xf0, []uint8{0x3f, 0x7f, 0xff}},
+		{0xf0, 0x10, []uint8{}},
+	} {
+		seq := []uint8{}
+		for hi, arc := range gf2.Covering(x.lo, x.hi) {
+			it.Then(t).Should(it.Equal(hi, arc.Hi))
+			seq = append(seq, hi)
+		}
+		it.Then(t).Should(it.Seq(seq).Equal(x.arcs...))
+	}
+}
+
+func TestFieldExportImport(t *testing.T) {
+	gf2 := skiplist.NewGF2[uint16]()
+	for i := 0; i < 100; i++ {
+		gf2.Add(uint16(i * 601))
+	}
+
+	b, err := json.Marshal(gf2.Export())
+	it.Then(t).Should(it.Nil(err))
+
+	var arcs []skiplist.Arc[uint16]
+	err = json.Unmarshal(b, &arcs)
+	it.Then(t).Should(it.Nil(err))
+
+	val := new(skiplist.GF2[uint16])
+	err = val.Import(arcs)
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(val.Length(), gf2.Length()),
+		it.Seq(val.Export()).Equal(gf2.Export()...),
+	)
+
+	for i := 0; i < 100; i++ {
+		a, _ := gf2.Get(uint16(i * 653))
+		b, _ := val.Get(uint16(i * 653))
+		it.Then(t).Should(it.Equal(a, b))
+	}
+
+	t.Run("NonContinuous", func(t *testing.T) {
+		for _, arcs := range [][]skiplist.Arc[uint16]{
+			{},
+			{{Lo: 1, Hi: 0xffff}},
+			{{Lo: 0, Hi: 0xfffe}},
+			{{Lo: 0, Hi: 10}, {Lo: 12, Hi: 0xffff}},
+			{{Lo: 0, Hi: 10}, {Lo: 11, Hi: 5}, {Lo: 6, Hi: 0xffff}},
+			{{Lo: 0, Hi: 0xffff}, {Lo: 0, Hi: 5}, {Lo: 6, Hi: 0xffff}},
+		} {
+			it.Then(t).Should(
+				it.Equal(val.Import(arcs), skiplist.ErrNonContinuous),
+			)
+		}
+
+		for _, arcs := range [][]skiplist.Arc[uint16]{
+			{{Rank: 17, Lo: 0, Hi: 0xffff}},
+			{{Rank: 3, Lo: 0, Hi: 6}, {Rank: 15, Lo: 7, Hi: 0xffff}},
+		} {
+			it.Then(t).Should(
+				it.Equal(val.Import(arcs), skiplist.ErrInvalidRank),
+			)
+		}
+
+		it.Then(t).Should(it.Equal(val.Length(), gf2.Length()))
+	})
+}
+
+func TestFieldSigned(t *testing.T) {
+	for _, x := range []int8{-128, -1, 0, 1, 127} {
+		it.Then(t).Should(
+			it.Equal(skiplist.ToSigned[int8](skiplist.FromSigned[uint8](x)), x),
+			it.Equal(skiplist.ToSigned[int16](skiplist.FromSigned[uint16](int16(x))), int16(x)),
+		)
+	}
+
+	it.Then(t).Should(
+		it.Equal(skiplist.FromSigned[uint8](int8(-128)), 0x00),
+		it.Equal(skiplist.FromSigned[uint8](int8(0)), 0x80),
+		it.Equal(skiplist.FromSigned[uint8](int8(127)), 0xff),
+		it.Equal(skiplist.FromSigned[uint16](int16(-1)), 0x7fff),
+		it.Equal(skiplist.FromSigned[uint64](int64(-1)), 0x7fffffffffffffff),
+		it.Fail(func() { skiplist.FromSigned[uint16](int8(-1)) }),
+		it.Fail(func() { skiplist.FromSigned[uint8](int16(-1)) }),
+		it.Fail(func() { skiplist.ToSigned[int32](uint64(0)) }),
+	)
+
+	gf2 := skiplist.NewGF2[uint32]()
+	gf2.Add(0)
+
+	lo, _ := gf2.Get(skiplist.FromSigned[uint32](int32(-10)))
+	hi, _ := gf2.Get(skiplist.FromSigned[uint32](int32(10)))
+	it.Then(t).Should(
+		it.Equal(skiplist.ToSigned[int32](lo.Lo), -1<<31),
+		it.Equal(skiplist.ToSigned[int32](lo.Hi), -1),
+		it.Equal(skiplist.ToSigned[int32](hi.Lo), 0),
+	)
+
+	t.Run("Uint128", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Equal(skiplist.FromUint128[uint64](0xabcd, 0xffff), 0xabcd),
+			it.Equal(skiplist.FromUint128[uint16](0xabcd000000000000, 0xffff), 0xabcd),
+		)
+	})
+}
+
+func TestFieldNeighbors(t *testing.T) {
+	gf2 := skiplist.NewGF2[uint8]()
+
+	prev, next := gf2.Neighbors(0x10)
+	it.Then(t).Should(
+		it.Equal(prev.Hi, 0xff),
+		it.Equal(next.Hi, 0xff),
+	)
+
+	gf2.Add(0x39)
+	gf2.Add(0x39)
+
+	// [00-3f] [40-7f] [80-ff]
+	for _, x := range [][]uint8{
+		{0x00, 0xff, 0x7f},
+		{0x50, 0x3f, 0xff},
+		{0xf0, 0x7f, 0x3f},
+	} {
+		prev, next := gf2.Neighbors(x[0])
+		it.Then(t).Should(
+			it.Equal(prev.Hi, x[1]),
+			it.Equal(next.Hi, x[2]),
+			it.Equal(gf2.Predecessor(x[0]), prev),
+		)
+	}
+}
+
+func TestFieldLoad(t *testing.T) {
+	gf2 := skiplist.NewGF2[uint8]()
+	gf2.Add(0x39)
+	gf2.Add(0x39)
+
+	// [00-3f] [40-7f] [80-ff]
+	gf2.Hit(0x41, 10)
+	gf2.Hit(0x50, 10)
+	arc := gf2.Hit(0x90, 5)
+	it.Then(t).Should(it.Equal(arc.Load, 5))
+
+	hd, tl := gf2.SplitHottest()
+	it.Then(t).Should(
+		it.Equal(hd, skiplist.Arc[uint8]{Rank: 5, Lo: 0x40, Hi: 0x5f, Load: 10}),
+		it.Equal(tl, skiplist.Arc[uint8]{Rank: 5, Lo: 0x60, Hi: 0x7f, Load: 10}),
+	)
+
+	hd, tl = gf2.SplitLargest()
+	it.Then(t).Should(
+		it.Equal(hd, skiplist.Arc[uint8]{Rank: 6, Lo: 0x80, Hi: 0xbf, Load: 2}),
+		it.Equal(tl, skiplist.Arc[uint8]{Rank: 6, Lo: 0xc0, Hi: 0xff, Load: 3}),
+	)
+
+	arc, _ = gf2.Merge(0x40)
+	it.Then(t).Should(it.Equal(arc.Load, 20))
+
+	hd, tl = gf2.SplitAt(0x40, 0x4f)
+	it.Then(t).Should(
+		it.Equal(hd.Load, 5),
+		it.Equal(tl.Load, 15),
+	)
+
+	gf2.ResetLoad()
+	for _, arc := range gf2.All() {
+		it.Then(t).Should(it.Equal(arc.Load, 0))
+	}
+
+	t.Run("Unsplittable", func(t *testing.T) {
+		gf2 := skiplist.NewGF2[uint8]()
+		for i := 0; i < 300; i++ {
+			gf2.SplitLargest()
+		}
+
+		hd, tl := gf2.SplitHottest()
+		it.Then(t).Should(
+			it.Equal(gf2.Length(), 256),
+			it.Equal(hd, tl),
+		)
+	})
+}
+
+func TestFieldConfig(t *testing.T) {
+	gf2 := skiplist.NewGF2(skiplist.MapWithBlockSize[uint8, skiplist.Arc[uint8]](16))
+	gf2.Add(0x80)
+
+	it.Then(t).Should(
+		it.Equal(gf2.Length(), 2),
+		it.Fail(func() {
+			skiplist.NewGF2(skiplist.MapWithDescending[uint8, skiplist.Arc[uint8]]())
+		}).Contain("unsupported configuration of field"),
+		it.Fail(func() {
+			skiplist.NewGF2(skiplist.MapWithCapacity[uint8, skiplist.Arc[uint8]](1, skiplist.EvictMin, nil))
+		}).Contain("unsupported configuration of field"),
+		it.Fail(func() {
+			skiplist.NewGF2(skiplist.MapWithOnCut(func(uint8, skiplist.Arc[uint8]) {}))
+		}).Contain("unsupported configuration of field"),
+	)
+}
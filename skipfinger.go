@@ -0,0 +1,68 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+// finger is the path to the last accessed key. The search of the key that
+// is greater than the last one starts from the finger, it takes O(log d)
+// where d is a distance between keys, which benefits local access patterns
+// (e.g. time-series appends, ingest of almost sorted keys).
+//
+// The finger is moved by Put only and dropped by any other structural change
+// of the map. Reads start from the finger but never move it, concurrent
+// readers are safe.
+type finger[K any, V any] struct {
+	path  [L]*Pair[K, V]
+	rank  [L]int
+	valid bool
+}
+
+// reset the finger, it is safe to call on nil finger
+func (f *finger[K, V]) reset() {
+	if f != nil {
+		f.valid = false
+	}
+}
+
+// follow the pair inserted after the path
+func (f *finger[K, V]) follow(path [L]*Pair[K, V], rank [L]int, el *Pair[K, V]) {
+	f.path, f.rank, f.valid = path, rank, true
+
+	pos := rank[0] + 1
+	for level := 0; level < L && path[level].Fingers[level] == el; level++ {
+		f.path[level], f.rank[level] = el, pos
+	}
+}
+
+// skipFinger is skip algorithm that starts from the finger if the key is
+// greater than the last accessed one. The finger is moved to the key.
+func (kv *Map[K, V]) skipFinger(key K) (*Pair[K, V], [L]*Pair[K, V], [L]int) {
+	f := kv.finger
+	if f.valid && (f.path[0] == kv.head || kv.compare(f.path[0].Key, key) < 0) {
+		el := kv.skipFrom(key, &f.path, &f.rank)
+		return el, f.path, f.rank
+	}
+
+	el, path, rank := kv.skipWithRank(key)
+	f.path, f.rank, f.valid = path, rank, true
+
+	return el, path, rank
+}
+
+// seekFinger is skip algorithm that starts from the finger if the key is
+// greater than the last accessed one. The finger is not moved.
+func (kv *Map[K, V]) seekFinger(key K) *Pair[K, V] {
+	f := kv.finger
+	if f.valid && (f.path[0] == kv.head || kv.compare(f.path[0].Key, key) < 0) {
+		path, rank := f.path, f.rank
+		return kv.skipFrom(key, &path, &rank)
+	}
+
+	el, _ := kv.Skip(0, key)
+	return el
+}
@@ -11,15 +11,33 @@ package skiplist
 import (
 	"fmt"
 	"strings"
+
+	"github.com/fogfish/skiplist/ord"
 )
 
-type HashMap[K Key, V any] struct {
+// HashMap is ordered map with O(1) lookup, values are kept in the hash map,
+// keys are ordered by the skip set. Keys are any comparable type ordered by
+// the type class. The type class defines equality of keys. The map created
+// with NewHashMapWith accepts type classes that disagree with == operator,
+// the lookup of a key that is equal by type class only costs O(log n).
+type HashMap[K comparable, V any] struct {
 	keys   *Set[K]
 	values map[K]V
+
+	// equality of type class is == operator, the lookup never falls back
+	// to the ordered set of keys
+	exact bool
 }
 
 func NewHashMap[K Key, V any](opts ...SetConfig[K]) *HashMap[K, V] {
-	keys := NewSet(opts...)
+	kv := NewHashMapWith[K, V](ord.Type[K]{}, opts...)
+	kv.exact = true
+	return kv
+}
+
+// NewHashMapWith creates instance of HashMap, keys are ordered by the given type class
+func NewHashMapWith[K comparable, V any](cmp ord.Ord[K], opts ...SetConfig[K]) *HashMap[K, V] {
+	keys := NewSetWith(cmp, opts...)
 
 	return &HashMap[K, V]{
 		keys:   keys,
@@ -53,35 +71,106 @@ func (kv *HashMap[K, V]) Skip(level int, key K) (*Element[K], [L]*Element[K]) {
 	return kv.keys.Skip(level, key)
 }
 
-func (kv *HashMap[K, V]) Put(key K, val V) (bool, *Element[K]) {
-	if _, has := kv.values[key]; has {
-		kv.values[key] = val
-		return false, nil
+// canonical key stored by the map, the key equal to the given one by type
+// class. Values are kept by canonical keys, the lookup falls back to the
+// ordered set if type class disagrees with == operator (e.g. time.Time
+// of different locations).
+func (kv *HashMap[K, V]) canonical(key K) (K, bool) {
+	if _, has := kv.values[key]; has || kv.exact {
+		return key, has
 	}
 
+	if el := kv.keys.Find(key); el != nil {
+		return el.Key, true
+	}
+
+	return key, false
+}
+
+// Put key-value pair into map, returns true if pair is new
+func (kv *HashMap[K, V]) Put(key K, val V) bool {
+	key, has := kv.canonical(key)
 	kv.values[key] = val
+	if has {
+		return false
+	}
+
 	return kv.keys.Add(key)
 }
 
-func (kv *HashMap[K, V]) Get(key K) (V, bool) {
-	val, has := kv.values[key]
-	return val, has
+// GetOrInsert returns existing value and true if key exists, otherwise puts
+// the value and returns it with false
+func (kv *HashMap[K, V]) GetOrInsert(key K, val V) (V, bool) {
+	if key, has := kv.canonical(key); has {
+		return kv.values[key], true
+	}
+
+	kv.values[key] = val
+	kv.keys.Add(key)
+	return val, false
 }
 
-func (kv *HashMap[K, V]) Cut(key K) (V, bool) {
-	val, has := kv.values[key]
-	if has {
+// Compute performs read-modify-write of the value. The function receives
+// existing value (if any) and returns a new value, false requests deletion
+// of the key. Returns the value and true if the key exists after the operation.
+func (kv *HashMap[K, V]) Compute(key K, f func(V, bool) (V, bool)) (V, bool) {
+	key, has := kv.canonical(key)
+	old := kv.values[key]
+	val, keep := f(old, has)
+
+	switch {
+	case keep:
+		kv.values[key] = val
+		if !has {
+			kv.keys.Add(key)
+		}
+		return val, true
+	case has:
 		delete(kv.values, key)
 		kv.keys.Cut(key)
 	}
 
-	return val, has
+	return *new(V), false
+}
+
+func (kv *HashMap[K, V]) Get(key K) (V, bool) {
+	if val, has := kv.values[key]; has || kv.exact {
+		return val, has
+	}
+
+	if el := kv.keys.Find(key); el != nil {
+		return kv.values[el.Key], true
+	}
+
+	return *new(V), false
+}
+
+func (kv *HashMap[K, V]) Cut(key K) (V, bool) {
+	key, has := kv.canonical(key)
+	if !has {
+		return *new(V), false
+	}
+
+	val := kv.values[key]
+	delete(kv.values, key)
+	kv.keys.Cut(key)
+
+	return val, true
+}
+
+func (kv *HashMap[K, V]) Clear() {
+	kv.keys.Clear()
+	kv.values = make(map[K]V)
 }
 
 func (kv *HashMap[K, V]) Keys() *Element[K] {
 	return kv.keys.Values()
 }
 
+func (kv *HashMap[K, V]) KeysOn(level int) *Element[K] {
+	return kv.keys.ValuesOn(level)
+}
+
 func (kv *HashMap[K, V]) Successor(key K) *Element[K] {
 	return kv.keys.Successor(key)
 }
@@ -98,5 +187,19 @@ func (kv *HashMap[K, V]) Split(key K) *HashMap[K, V] {
 	return &HashMap[K, V]{
 		keys:   keys,
 		values: values,
+		exact:  kv.exact,
+	}
+}
+
+func (kv *HashMap[K, V]) Clone() *HashMap[K, V] {
+	values := make(map[K]V, len(kv.values))
+	for k, v := range kv.values {
+		values[k] = v
+	}
+
+	return &HashMap[K, V]{
+		keys:   kv.keys.Clone(),
+		values: values,
+		exact:  kv.exact,
 	}
 }
@@ -17,6 +17,7 @@ import (
 
 	"github.com/fogfish/it/v2"
 	"github.com/fogfish/skiplist"
+	"github.com/fogfish/skiplist/ord"
 )
 
 // ---------------------------------------------------------------
@@ -31,14 +32,12 @@ func HashMapSuite[K skiplist.Key](t *testing.T, seq []K) {
 	kv := skiplist.NewHashMap[K, K]()
 
 	t.Run("Put", func(t *testing.T) {
-		f := func(has bool, node *skiplist.Element[K]) bool { return has }
-
 		for _, el := range seq {
 			it.Then(t).Should(
-				it.True(f(kv.Put(el, el))),
+				it.True(kv.Put(el, el)),
 			).ShouldNot(
-				it.True(f(kv.Put(el, *new(K)))),
-				it.True(f(kv.Put(el, el))),
+				it.True(kv.Put(el, *new(K))),
+				it.True(kv.Put(el, el)),
 			)
 		}
 
@@ -92,6 +91,29 @@ func HashMapSuite[K skiplist.Key](t *testing.T, seq []K) {
 		)
 	})
 
+	t.Run("Clone", func(t *testing.T) {
+		clone := kv.Clone()
+		clone.Cut(sorted[0])
+
+		_, has := kv.Get(sorted[0])
+		it.Then(t).Should(
+			it.True(has),
+			it.Equal(kv.Length(), len(sorted)),
+			it.Equal(clone.Length(), len(sorted)-1),
+		)
+
+		values := clone.Keys()
+		for i := 1; i < len(sorted); i++ {
+			val, has := clone.Get(values.Key)
+			it.Then(t).Should(
+				it.True(has),
+				it.Equal(val, sorted[i]),
+				it.Equal(values.Key, sorted[i]),
+			)
+			values = values.Next()
+		}
+	})
+
 	t.Run("Cut", func(t *testing.T) {
 		for _, el := range seq {
 			val, has := kv.Cut(el)
@@ -145,6 +167,21 @@ func HashMapSuite[K skiplist.Key](t *testing.T, seq []K) {
 		}
 	})
 
+	t.Run("Clear", func(t *testing.T) {
+		other := skiplist.NewHashMap[K, K]()
+		for _, x := range seq {
+			other.Put(x, x)
+		}
+		other.Clear()
+
+		_, has := other.Get(seq[0])
+		it.Then(t).Should(
+			it.Equal(other.Length(), 0),
+			it.True(other.Keys() == nil),
+		).ShouldNot(
+			it.True(has),
+		)
+	})
 }
 
 func TestHashMapOfIntPutGetCut(t *testing.T) {
@@ -165,6 +202,113 @@ func TestHashMapOfStringPutGetCut(t *testing.T) {
 	HashMapSuite(t, []string{"67", "aa", "b2", "d9", "56", "bd", "7c", "c6", "21", "af", "22", "cf", "b1", "69", "cb", "a8"})
 }
 
+func TestHashMapGetOrInsert(t *testing.T) {
+	kv := skiplist.NewHashMap[int, string]()
+
+	val, has := kv.GetOrInsert(1, "a")
+	it.Then(t).Should(it.True(!has), it.Equal(val, "a"))
+
+	val, has = kv.GetOrInsert(1, "b")
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, "a"),
+		it.Equal(kv.Length(), 1),
+		it.Equal(kv.Keys().Key, 1),
+	)
+}
+
+func TestHashMapCompute(t *testing.T) {
+	kv := skiplist.NewHashMap[int, int]()
+	incr := func(v int, has bool) (int, bool) { return v + 1, true }
+
+	kv.Compute(1, incr)
+	val, has := kv.Compute(1, incr)
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, 2),
+		it.Equal(kv.Length(), 1),
+	)
+
+	_, has = kv.Compute(1, func(v int, has bool) (int, bool) { return 0, false })
+	_, had := kv.Get(1)
+	it.Then(t).Should(
+		it.True(!has),
+		it.True(!had),
+		it.Equal(kv.Length(), 0),
+		it.True(kv.Keys() == nil),
+	)
+}
+
+func TestHashMapWith(t *testing.T) {
+	type ID struct{ Seq, Node int }
+
+	kv := skiplist.NewHashMapWith[ID, string](
+		ord.From[ID](func(a, b ID) int {
+			if c := ord.Int.Compare(a.Seq, b.Seq); c != 0 {
+				return c
+			}
+			return ord.Int.Compare(a.Node, b.Node)
+		}),
+	)
+
+	kv.Put(ID{2, 1}, "c")
+	kv.Put(ID{1, 2}, "b")
+	kv.Put(ID{1, 1}, "a")
+
+	vals := []string{}
+	for _, v := range kv.All() {
+		vals = append(vals, v)
+	}
+
+	val, has := kv.Get(ID{1, 2})
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, "b"),
+		it.Seq(vals).Equal("a", "b", "c"),
+	)
+
+	now := time.Now().Truncate(time.Second)
+	tm := skiplist.NewHashMapWith[time.Time, int](ord.Time)
+	tm.Put(now.Add(time.Second), 2)
+	tm.Put(now, 1)
+
+	it.Then(t).Should(
+		it.Equal(tm.Keys().Key, now),
+		it.Equal(tm.Length(), 2),
+	)
+}
+
+func TestHashMapWithTimeLocation(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	kv := skiplist.NewHashMapWith[time.Time, int](ord.Time)
+
+	isNew := kv.Put(at, 1)
+	isAlt := kv.Put(at.UTC(), 2)
+	val, has := kv.Get(at)
+	alt, _ := kv.Get(at.UTC())
+
+	it.Then(t).Should(
+		it.True(isNew),
+		it.True(!isAlt),
+		it.Equal(kv.Length(), 1),
+		it.True(has),
+		it.Equal(val, 2),
+		it.Equal(alt, 2),
+	)
+
+	cut, had := kv.Cut(at.UTC())
+	_, has = kv.Get(at)
+	_, hasUTC := kv.Get(at.UTC())
+
+	it.Then(t).Should(
+		it.True(had),
+		it.Equal(cut, 2),
+		it.Equal(kv.Length(), 0),
+		it.True(!has),
+		it.True(!hasUTC),
+	)
+}
+
 // ---------------------------------------------------------------
 
 func HashMapBench[K skiplist.Key](b *testing.B, gen func(int) K) {
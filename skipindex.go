@@ -0,0 +1,124 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// IndexedMap is ordered map with secondary index of values. The index is
+// skip map ordered by sort key derived from the value, ties are ordered by
+// key. Put and Cut keep both structures in sync.
+type IndexedMap[K any, V any, S any] struct {
+	kv    *Map[K, indexed[V, S]]
+	index *Map[ikey[K, S], V]
+	by    func(V) S
+}
+
+// value of the map with its sort key
+type indexed[V any, S any] struct {
+	value V
+	sort  S
+}
+
+// key of the secondary index
+type ikey[K any, S any] struct {
+	sort S
+	key  K
+}
+
+// NewIndexedMap creates instance of map with secondary index, the sort key
+// of the index is derived from value by the function
+func NewIndexedMap[K Key, V any, S Key](by func(V) S) *IndexedMap[K, V, S] {
+	return NewIndexedMapWith[K, V, S](ord.Type[K]{}, ord.Type[S]{}, by)
+}
+
+// NewIndexedMapWith creates instance of map with secondary index, keys and
+// sort keys are ordered by the given type classes
+func NewIndexedMapWith[K any, V any, S any](keys ord.Ord[K], sort ord.Ord[S], by func(V) S) *IndexedMap[K, V, S] {
+	compare := func(a, b ikey[K, S]) int {
+		if c := sort.Compare(a.sort, b.sort); c != 0 {
+			return c
+		}
+
+		return keys.Compare(a.key, b.key)
+	}
+
+	return &IndexedMap[K, V, S]{
+		kv:    NewMapWith[K, indexed[V, S]](keys),
+		index: NewMapFunc[ikey[K, S], V](compare),
+		by:    by,
+	}
+}
+
+// Length returns number of keys
+func (kv *IndexedMap[K, V, S]) Length() int {
+	return kv.kv.Length()
+}
+
+// Put value of the key, returns true if key is new
+func (kv *IndexedMap[K, V, S]) Put(key K, val V) bool {
+	sort := kv.by(val)
+
+	isNew, el := kv.kv.getOrInsert(key, indexed[V, S]{value: val, sort: sort})
+	if !isNew {
+		kv.index.Cut(ikey[K, S]{sort: el.Value.sort, key: key})
+		el.Value = indexed[V, S]{value: val, sort: sort}
+	}
+
+	kv.index.Put(ikey[K, S]{sort: sort, key: key}, val)
+
+	return isNew
+}
+
+// Get value of the key
+func (kv *IndexedMap[K, V, S]) Get(key K) (V, bool) {
+	val, has := kv.kv.Get(key)
+	return val.value, has
+}
+
+// Cut key from the map, returns the value and true if key is removed
+func (kv *IndexedMap[K, V, S]) Cut(key K) (V, bool) {
+	val, has := kv.kv.Cut(key)
+	if !has {
+		return *new(V), false
+	}
+
+	kv.index.Cut(ikey[K, S]{sort: val.sort, key: key})
+
+	return val.value, true
+}
+
+// All pairs in key order
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *IndexedMap[K, V, S]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := kv.kv.Values(); e != nil; e = e.Next() {
+			if !yield(e.Key, e.Value.value) {
+				return
+			}
+		}
+	}
+}
+
+// ByValue pairs in order of sort key
+//
+//	for k, v := range kv.ByValue() { /* ... */ }
+func (kv *IndexedMap[K, V, S]) ByValue() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := kv.index.Values(); e != nil; e = e.Next() {
+			if !yield(e.Key.key, e.Value) {
+				return
+			}
+		}
+	}
+}
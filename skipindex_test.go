@@ -0,0 +1,58 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestIndexedMap(t *testing.T) {
+	kv := skiplist.NewIndexedMap[string, int, int](func(v int) int { return v })
+
+	it.Then(t).Should(
+		it.Equal(kv.Put("a", 30), true),
+		it.Equal(kv.Put("b", 10), true),
+		it.Equal(kv.Put("c", 20), true),
+		it.Equal(kv.Put("d", 10), true),
+		it.Equal(kv.Put("a", 5), false),
+		it.Equal(kv.Length(), 4),
+	)
+
+	keys := func(seq func(func(string, int) bool)) []string {
+		out := []string{}
+		for k := range seq {
+			out = append(out, k)
+		}
+		return out
+	}
+
+	it.Then(t).Should(
+		it.Seq(keys(kv.All())).Equal("a", "b", "c", "d"),
+		it.Seq(keys(kv.ByValue())).Equal("a", "b", "d", "c"),
+	)
+
+	val, has := kv.Cut("b")
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, 10),
+		it.Seq(keys(kv.ByValue())).Equal("a", "d", "c"),
+	)
+
+	val, has = kv.Get("c")
+	_, had := kv.Cut("b")
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, 20),
+		it.True(!had),
+		it.Equal(kv.Length(), 3),
+	)
+}
@@ -0,0 +1,224 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"math/rand"
+	"time"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// Interval of keys [Lo, Hi], both bounds are inclusive
+type Interval[K any] struct {
+	Lo, Hi K
+}
+
+// IntervalMap is augmented skip list of intervals ordered by lower bound.
+// Each finger holds the maximum upper bound of intervals it skips over, so
+// that overlap queries skip segments of the list that end before the query.
+// Overlapping takes O(log n + k), where k is number of reported intervals.
+type IntervalMap[K any, V any] struct {
+	head    *inode[K, V]
+	length  int
+	random  rand.Source
+	compare func(K, K) int
+}
+
+type inode[K any, V any] struct {
+	key     Interval[K]
+	value   V
+	fingers []*inode[K, V]
+
+	// maximum upper bound of intervals in the segment [node, fingers[i])
+	// at each level, the head node has no maximum
+	max []K
+}
+
+// NewIntervalMap creates instance of interval map
+func NewIntervalMap[K Key, V any]() *IntervalMap[K, V] {
+	return NewIntervalMapWith[K, V](ord.Type[K]{})
+}
+
+// NewIntervalMapWith creates instance of interval map, keys are ordered
+// by the given type class
+func NewIntervalMapWith[K any, V any](cmp ord.Ord[K]) *IntervalMap[K, V] {
+	return &IntervalMap[K, V]{
+		head:    &inode[K, V]{fingers: make([]*inode[K, V], L), max: make([]K, L)},
+		random:  rand.NewSource(time.Now().UnixNano()),
+		compare: cmp.Compare,
+	}
+}
+
+func (kv *IntervalMap[K, V]) Length() int {
+	return kv.length
+}
+
+// order intervals by lower bound, then by upper bound
+func (kv *IntervalMap[K, V]) order(a, b Interval[K]) int {
+	if c := kv.compare(a.Lo, b.Lo); c != 0 {
+		return c
+	}
+
+	return kv.compare(a.Hi, b.Hi)
+}
+
+func (kv *IntervalMap[K, V]) skip(key Interval[K], path *[L]*inode[K, V]) *inode[K, V] {
+	node := kv.head
+	for lev := L - 1; lev >= 0; lev-- {
+		for next := node.fingers[lev]; next != nil && kv.order(next.key, key) < 0; next = node.fingers[lev] {
+			node = next
+		}
+		path[lev] = node
+	}
+
+	return node.fingers[0]
+}
+
+// Put interval into map, returns true if interval is new
+func (kv *IntervalMap[K, V]) Put(lo, hi K, val V) bool {
+	var path [L]*inode[K, V]
+
+	key := Interval[K]{Lo: lo, Hi: hi}
+	el := kv.skip(key, &path)
+	if el != nil && kv.order(el.key, key) == 0 {
+		el.value = val
+		return false
+	}
+
+	// See: https://golang.org/src/math/rand/rand.go#L150
+	p := float64(kv.random.Int63()) / (1 << 63)
+
+	level := 0
+	for level < L && p < probabilityTable[level] {
+		level++
+	}
+
+	el = &inode[K, V]{
+		key:     key,
+		value:   val,
+		fingers: make([]*inode[K, V], level),
+		max:     make([]K, level),
+	}
+
+	for lev := 0; lev < level; lev++ {
+		el.fingers[lev] = path[lev].fingers[lev]
+		path[lev].fingers[lev] = el
+	}
+
+	kv.length++
+	kv.augment(&path, el)
+
+	return true
+}
+
+// Get value of the interval
+func (kv *IntervalMap[K, V]) Get(lo, hi K) (V, bool) {
+	var path [L]*inode[K, V]
+
+	key := Interval[K]{Lo: lo, Hi: hi}
+	el := kv.skip(key, &path)
+	if el != nil && kv.order(el.key, key) == 0 {
+		return el.value, true
+	}
+
+	return *new(V), false
+}
+
+// Cut interval from the map, returns the value and true if interval is removed
+func (kv *IntervalMap[K, V]) Cut(lo, hi K) (V, bool) {
+	var path [L]*inode[K, V]
+
+	key := Interval[K]{Lo: lo, Hi: hi}
+	el := kv.skip(key, &path)
+	if el == nil || kv.order(el.key, key) != 0 {
+		return *new(V), false
+	}
+
+	for lev := range el.fingers {
+		path[lev].fingers[lev] = el.fingers[lev]
+	}
+
+	kv.length--
+	kv.augment(&path, nil)
+
+	return el.value, true
+}
+
+// augment restores maximum of nodes on the path and of the new node.
+// Levels are updated bottom-up, the maximum at level i is computed from
+// maximums of level i-1.
+func (kv *IntervalMap[K, V]) augment(path *[L]*inode[K, V], el *inode[K, V]) {
+	for lev := 0; lev < L; lev++ {
+		if el != nil && lev < len(el.fingers) {
+			kv.reduce(el, lev)
+		}
+
+		if path[lev] != kv.head {
+			kv.reduce(path[lev], lev)
+		}
+	}
+}
+
+// reduce computes maximum of the segment [node, node.fingers[lev])
+func (kv *IntervalMap[K, V]) reduce(node *inode[K, V], lev int) {
+	if lev == 0 {
+		node.max[0] = node.key.Hi
+		return
+	}
+
+	top := node.max[lev-1]
+	for x := node.fingers[lev-1]; x != nil && x != node.fingers[lev]; x = x.fingers[lev-1] {
+		if kv.compare(x.max[lev-1], top) > 0 {
+			top = x.max[lev-1]
+		}
+	}
+
+	node.max[lev] = top
+}
+
+// Overlapping intervals of [lo, hi] in order of lower bound
+//
+//	for key, val := range kv.Overlapping(lo, hi) { /* ... */ }
+func (kv *IntervalMap[K, V]) Overlapping(lo, hi K) iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		node := kv.head.fingers[0]
+		for node != nil && kv.compare(node.key.Lo, hi) <= 0 {
+			// skip the longest segment that ends before the query
+			lev := len(node.fingers) - 1
+			for lev >= 0 && kv.compare(node.max[lev], lo) >= 0 {
+				lev--
+			}
+
+			if lev >= 0 {
+				node = node.fingers[lev]
+				continue
+			}
+
+			if !yield(node.key, node.value) {
+				return
+			}
+			node = node.fingers[0]
+		}
+	}
+}
+
+// All intervals in order of lower bound
+//
+//	for key, val := range kv.All() { /* ... */ }
+func (kv *IntervalMap[K, V]) All() iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		for node := kv.head.fingers[0]; node != nil; node = node.fingers[0] {
+			if !yield(node.key, node.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestIntervalMap(t *testing.T) {
+	kv := skiplist.NewIntervalMap[int, int]()
+
+	it.Then(t).Should(
+		it.Equal(kv.Put(10, 20, 1), true),
+		it.Equal(kv.Put(15, 30, 2), true),
+		it.Equal(kv.Put(40, 50, 3), true),
+		it.Equal(kv.Put(10, 20, 4), false),
+		it.Equal(kv.Length(), 3),
+	)
+
+	val, has := kv.Get(10, 20)
+	it.Then(t).Should(it.True(has), it.Equal(val, 4))
+
+	seq := []skiplist.Interval[int]{}
+	for key := range kv.Overlapping(18, 25) {
+		seq = append(seq, key)
+	}
+	it.Then(t).Should(
+		it.Seq(seq).Equal(
+			skiplist.Interval[int]{Lo: 10, Hi: 20},
+			skiplist.Interval[int]{Lo: 15, Hi: 30},
+		),
+	)
+
+	val, has = kv.Cut(15, 30)
+	it.Then(t).Should(it.True(has), it.Equal(val, 2))
+
+	_, has = kv.Cut(15, 30)
+	it.Then(t).Should(it.True(!has), it.Equal(kv.Length(), 2))
+}
+
+func TestIntervalMapOverlapping(t *testing.T) {
+	kv := skiplist.NewIntervalMap[int, int]()
+	ref := map[skiplist.Interval[int]]int{}
+
+	for i := 0; i < 5000; i++ {
+		lo := rand.Intn(1000)
+		key := skiplist.Interval[int]{Lo: lo, Hi: lo + rand.Intn(50)}
+		if rand.Intn(4) == 0 {
+			_, had := ref[key]
+			delete(ref, key)
+			_, has := kv.Cut(key.Lo, key.Hi)
+			it.Then(t).Should(it.Equal(has, had))
+		} else {
+			ref[key] = i
+			kv.Put(key.Lo, key.Hi, i)
+		}
+	}
+
+	it.Then(t).Should(it.Equal(kv.Length(), len(ref)))
+
+	for i := 0; i < 100; i++ {
+		lo := rand.Intn(1100)
+		hi := lo + rand.Intn(20)
+
+		n := 0
+		for key, val := range kv.Overlapping(lo, hi) {
+			it.Then(t).Should(
+				it.LessOrEqual(key.Lo, hi),
+				it.GreaterOrEqual(key.Hi, lo),
+				it.Equal(val, ref[key]),
+			)
+			n++
+		}
+
+		expect := 0
+		for key := range ref {
+			if key.Lo <= hi && key.Hi >= lo {
+				expect++
+			}
+		}
+		it.Then(t).Should(it.Equal(n, expect))
+	}
+}
@@ -12,8 +12,11 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/fogfish/skiplist/ord"
 )
 
 // Each key-value pair is represented by a Pair in a skip structures. Each node has
@@ -22,10 +25,14 @@ import (
 // a node with a random level is inserted to represent the element. Random levels
 // are generated with a simple pattern: 50% are level 1, 25% are level 2, 12.5% are
 // level 3 and so on.
-type Pair[K Key, V any] struct {
+type Pair[K any, V any] struct {
 	Key     K
 	Value   V
 	Fingers []*Pair[K, V]
+
+	// number of elements skipped by each finger, the span of finger
+	// at level i is a distance (in level 0 hops) to the element Fingers[i].
+	spans []int
 }
 
 // Rank of node
@@ -66,7 +73,7 @@ func (el *Pair[K, V]) String() string {
 // --------------------------------------------------------------------------------------
 
 // Map of Elements
-type Map[K Key, V any] struct {
+type Map[K any, V any] struct {
 	//
 	// head of the list, the node is a lowest element
 	head *Pair[K, V]
@@ -82,30 +89,76 @@ type Map[K Key, V any] struct {
 	// random generator
 	random rand.Source
 
-	//
-	// buffer to estimate the skip path during insert / remove
-	// the buffer implements optimization of memory allocations
-	path [L]*Pair[K, V]
-
 	//
 	ptable [L]float64
 
 	// memory allocator for elements
 	malloc Allocator[K, Pair[K, V]]
+
+	// total ordering of keys
+	compare func(K, K) int
+
+	// keys are ordered in reverse of the type class
+	desc bool
+
+	// hash of keys, levels are derived from the hash instead of random
+	// generator if it is defined
+	hash func(K) uint64
+
+	// fast generator of geometric levels, it replaces random generator
+	// and probability table if it is defined
+	geometric *geometric
+
+	// nodes are shared with snapshot, the map is copied on next write
+	shared bool
+
+	// codec of values used by serialization
+	codec Codec[V]
+
+	// path to the last accessed key, nil if finger search is disabled
+	finger *finger[K, V]
+
+	// instrumentation of operations, nil if disabled
+	probe *Probe
+
+	// total ordering of keys without instrumentation, nil if probe is disabled
+	unprobed func(K, K) int
+
+	// maximum number of pairs, nil if the map is unbounded
+	bound *bound[K, V]
+
+	// hooks of mutations, nil if not defined
+	onPut func(key K, old, val V, updated bool)
+	onCut func(key K, val V)
+
+	// watchers of key ranges, nil if not watched
+	watch *watch[K, V]
 }
 
 // New create instance of SkipList
 func NewMap[K Key, V any](opts ...MapConfig[K, V]) *Map[K, V] {
-	head := &Pair[K, V]{Fingers: make([]*Pair[K, V], L)}
+	return NewMapFunc[K, V](ord.Type[K]{}.Compare, opts...)
+}
+
+// NewMapWith create instance of SkipList, keys are ordered by the given type class
+func NewMapWith[K any, V any](cmp ord.Ord[K], opts ...MapConfig[K, V]) *Map[K, V] {
+	return NewMapFunc[K, V](cmp.Compare, opts...)
+}
+
+// NewMapFunc create instance of SkipList, keys are ordered by the compare
+// function. The function is called directly, it avoids dynamic dispatch of
+// the type class.
+func NewMapFunc[K any, V any](compare func(a, b K) int, opts ...MapConfig[K, V]) *Map[K, V] {
+	head := &Pair[K, V]{Fingers: make([]*Pair[K, V], L), spans: make([]int, L)}
 
 	set := &Map[K, V]{
-		head:   head,
-		null:   *new(K),
-		length: 0,
-		random: rand.NewSource(time.Now().UnixNano()),
-		path:   [L]*Pair[K, V]{},
-		ptable: probabilityTable,
-		malloc: nil,
+		head:    head,
+		null:    *new(K),
+		length:  0,
+		random:  rand.NewSource(time.Now().UnixNano()),
+		ptable:  probabilityTable,
+		malloc:  nil,
+		compare: compare,
 	}
 
 	for _, opt := range opts {
@@ -115,6 +168,96 @@ func NewMap[K Key, V any](opts ...MapConfig[K, V]) *Map[K, V] {
 	return set
 }
 
+// NewMapFromSorted builds instance of SkipList from sorted pairs in O(n).
+// Levels are assigned deterministically, each level contains every 1/p-th
+// element of the level below. Keys must be unique and sorted in ascending order.
+func NewMapFromSorted[K Key, V any](pairs []Pair[K, V], opts ...MapConfig[K, V]) *Map[K, V] {
+	kv := NewMap(opts...)
+
+	b := newMapBuilder(kv)
+	for _, pair := range pairs {
+		if !b.append(pair.Key, pair.Value) {
+			panic("pairs are not sorted")
+		}
+	}
+
+	return kv
+}
+
+// sortedLevels assigns levels and spans of nodes appended in sorted order,
+// it is shared by builders of Map and Set. Levels are deterministic, each
+// level contains every 1/p-th node of the level below.
+type sortedLevels struct {
+	step int
+	rank [L]int
+}
+
+func newSortedLevels(ptable *[L]float64) sortedLevels {
+	step := int(math.Round(1 / ptable[1]))
+	if step < 2 {
+		step = 2
+	}
+
+	return sortedLevels{step: step}
+}
+
+// level of node at the position (from 1)
+func (s *sortedLevels) level(pos int) int {
+	lvl := 1
+	for n := s.step; lvl < L && pos%n == 0; n *= s.step {
+		lvl++
+	}
+	return lvl
+}
+
+// span of finger at the level from the tail to node at the position,
+// the node becomes tail of the level
+func (s *sortedLevels) span(level, pos int) int {
+	span := pos - s.rank[level]
+	s.rank[level] = pos
+	return span
+}
+
+// mapBuilder appends sorted pairs at the tail of empty map in O(1).
+type mapBuilder[K any, V any] struct {
+	kv     *Map[K, V]
+	levels sortedLevels
+	tail   [L]*Pair[K, V]
+}
+
+func newMapBuilder[K any, V any](kv *Map[K, V]) *mapBuilder[K, V] {
+	b := &mapBuilder[K, V]{kv: kv, levels: newSortedLevels(&kv.ptable)}
+	for i := range b.tail {
+		b.tail[i] = kv.head
+	}
+
+	return b
+}
+
+// append pair at the tail, returns false if key is not greater than
+// the last one
+func (b *mapBuilder[K, V]) append(key K, val V) bool {
+	if b.tail[0] != b.kv.head && b.kv.compare(b.tail[0].Key, key) >= 0 {
+		return false
+	}
+
+	pos := b.kv.length + 1
+	lvl := b.levels.level(pos)
+
+	el := b.kv.NewPair(key, lvl)
+	el.Key = key
+	el.Value = val
+
+	for level := 0; level < lvl; level++ {
+		b.tail[level].Fingers[level] = el
+		b.tail[level].spans[level] = b.levels.span(level, pos)
+		b.tail[level] = el
+	}
+
+	b.kv.length = pos
+	return true
+}
+
 // Cast set into string
 func (kv *Map[K, V]) String() string {
 	sb := strings.Builder{}
@@ -149,12 +292,12 @@ func (kv *Map[K, V]) Level() int {
 // of level i or higher that is to the left of the location of the
 // insertion/deletion.
 func (kv *Map[K, V]) Skip(level int, key K) (*Pair[K, V], [L]*Pair[K, V]) {
-	path := kv.path
+	var path [L]*Pair[K, V]
 
 	node := kv.head
 	next := node.Fingers
 	for lev := L - 1; lev >= level; lev-- {
-		for next[lev] != nil && next[lev].Key < key {
+		for next[lev] != nil && kv.compare(next[lev].Key, key) < 0 {
 			node = node.Fingers[lev]
 			next = node.Fingers
 		}
@@ -164,34 +307,341 @@ func (kv *Map[K, V]) Skip(level int, key K) (*Pair[K, V], [L]*Pair[K, V]) {
 	return next[level], path
 }
 
-func (kv *Map[K, V]) Put(key K, val V) (bool, *Pair[K, V]) {
-	el, path := kv.Skip(0, key)
+// skipWithRank is skip algorithm that also estimates position (rank) of each
+// node on the path. The position of head is 0, the first element is 1.
+func (kv *Map[K, V]) skipWithRank(key K) (*Pair[K, V], [L]*Pair[K, V], [L]int) {
+	var path [L]*Pair[K, V]
+	rank := [L]int{}
+
+	node := kv.head
+	next := node.Fingers
+	pos := 0
+	for lev := L - 1; lev >= 0; lev-- {
+		for next[lev] != nil && kv.compare(next[lev].Key, key) < 0 {
+			pos += node.spans[lev]
+			node = node.Fingers[lev]
+			next = node.Fingers
+		}
+		path[lev] = node
+		rank[lev] = pos
+	}
+
+	return next[0], path, rank
+}
+
+// Put key-value pair into map, returns true if pair is new
+func (kv *Map[K, V]) Put(key K, val V) bool {
+	isNew, _ := kv.put(key, val)
+	return isNew
+}
+
+// put key-value pair into map, returns the pair and true if pair is new
+func (kv *Map[K, V]) put(key K, val V) (bool, *Pair[K, V]) {
+	kv.detach()
+
+	if kv.probe != nil {
+		kv.probe.enter(probePut)
+		defer kv.probe.exit()
+	}
+
+	if kv.finger != nil {
+		el, path, rank := kv.skipFinger(key)
+		if el != nil && kv.compare(el.Key, key) == 0 {
+			kv.update(el, val)
+			return false, el
+		}
+
+		el = kv.insert(path, rank, key, val)
+		kv.finger.follow(path, rank, el)
+		kv.evict(el)
+		return true, el
+	}
+
+	el, path, rank := kv.skipWithRank(key)
+
+	if el != nil && kv.compare(el.Key, key) == 0 {
+		kv.update(el, val)
+		return false, el
+	}
+
+	el = kv.insert(path, rank, key, val)
+	kv.evict(el)
+	return true, el
+}
+
+// PutAll puts pairs to the map, returns number of new pairs.
+// Sorted runs of pairs are spliced starting from the last insertion point.
+func (kv *Map[K, V]) PutAll(pairs ...Pair[K, V]) int {
+	kv.detach()
+
+	var (
+		el   *Pair[K, V]
+		path [L]*Pair[K, V]
+		rank [L]int
+	)
+
+	n := 0
+	for i, pair := range pairs {
+		if i > 0 && kv.compare(pairs[i-1].Key, pair.Key) < 0 {
+			el = kv.skipFrom(pair.Key, &path, &rank)
+		} else {
+			el, path, rank = kv.skipWithRank(pair.Key)
+		}
+
+		if el != nil && kv.compare(el.Key, pair.Key) == 0 {
+			kv.update(el, pair.Value)
+			continue
+		}
+
+		pos := rank[0] + 1
+		el = kv.insert(path, rank, pair.Key, pair.Value)
+		for level := 0; level < L && path[level].Fingers[level] == el; level++ {
+			path[level], rank[level] = el, pos
+		}
+		n++
+	}
+
+	kv.evict(nil)
+	return n
+}
+
+// Merge puts all pairs of other map into the map, the other map is not changed.
+// The resolve function defines the value if key exists in both maps, it
+// receives value of the map and the other map. If resolve is nil then value
+// of the other map is used.
+func (kv *Map[K, V]) Merge(other *Map[K, V], resolve func(K, V, V) V) {
+	kv.detach()
+
+	var (
+		el   *Pair[K, V]
+		path [L]*Pair[K, V]
+		rank [L]int
+	)
+
+	for i := 0; i < L; i++ {
+		path[i] = kv.head
+	}
+
+	for e := other.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		el = kv.skipFrom(e.Key, &path, &rank)
+
+		if el != nil && kv.compare(el.Key, e.Key) == 0 {
+			if resolve != nil {
+				kv.update(el, resolve(e.Key, el.Value, e.Value))
+			} else {
+				kv.update(el, e.Value)
+			}
+			continue
+		}
+
+		pos := rank[0] + 1
+		el = kv.insert(path, rank, e.Key, e.Value)
+		for level := 0; level < L && path[level].Fingers[level] == el; level++ {
+			path[level], rank[level] = el, pos
+		}
+	}
+
+	kv.evict(nil)
+}
+
+// skipFrom continues skip algorithm from the given path, the path shall
+// points to nodes that are less than key.
+func (kv *Map[K, V]) skipFrom(key K, path *[L]*Pair[K, V], rank *[L]int) *Pair[K, V] {
+	top := 0
+	for top < L && path[top].Fingers[top] != nil && kv.compare(path[top].Fingers[top].Key, key) < 0 {
+		top++
+	}
+
+	node, pos := kv.head, 0
+	for lev := top - 1; lev >= 0; lev-- {
+		if rank[lev] > pos {
+			node, pos = path[lev], rank[lev]
+		}
+
+		for node.Fingers[lev] != nil && kv.compare(node.Fingers[lev].Key, key) < 0 {
+			pos += node.spans[lev]
+			node = node.Fingers[lev]
+		}
+		path[lev], rank[lev] = node, pos
+	}
+
+	return path[0].Fingers[0]
+}
+
+// GetOrInsert returns existing value and true if key exists, otherwise puts
+// the value and returns it with false
+func (kv *Map[K, V]) GetOrInsert(key K, val V) (V, bool) {
+	isNew, el := kv.getOrInsert(key, val)
+	return el.Value, !isNew
+}
+
+// getOrInsert returns existing pair or puts a new one, return true if pair is new
+func (kv *Map[K, V]) getOrInsert(key K, val V) (bool, *Pair[K, V]) {
+	kv.detach()
 
-	if el != nil && el.Key == key {
-		el.Value = val
+	el, path, rank := kv.skipWithRank(key)
+
+	if el != nil && kv.compare(el.Key, key) == 0 {
 		return false, el
 	}
 
-	rank, el := kv.CreatePair(L, key, val)
+	el = kv.insert(path, rank, key, val)
+	kv.evict(el)
+	return true, el
+}
+
+// GetOrCompute returns existing value and true if key exists, otherwise puts
+// the value computed by f and returns it with false.
+func (kv *Map[K, V]) GetOrCompute(key K, f func() V) (V, bool) {
+	kv.detach()
+
+	el, path, rank := kv.skipWithRank(key)
+
+	if el != nil && kv.compare(el.Key, key) == 0 {
+		return el.Value, true
+	}
+
+	val := f()
+	kv.evict(kv.insert(path, rank, key, val))
+	return val, false
+}
+
+// Compute performs read-modify-write of the value within a single traversal.
+// The function receives existing value (if any) and returns a new value,
+// false requests deletion of the key. Returns the value and true if the key
+// exists after the operation.
+func (kv *Map[K, V]) Compute(key K, f func(V, bool) (V, bool)) (V, bool) {
+	kv.detach()
+
+	el, path, rank := kv.skipWithRank(key)
+
+	if el != nil && kv.compare(el.Key, key) == 0 {
+		val, keep := f(el.Value, true)
+		if !keep {
+			kv.unlink(L, path, el)
+			return *new(V), false
+		}
+
+		kv.update(el, val)
+		return val, true
+	}
+
+	val, keep := f(*new(V), false)
+	if !keep {
+		return *new(V), false
+	}
+
+	kv.evict(kv.insert(path, rank, key, val))
+	return val, true
+}
+
+// CompareAndSwap updates the value of existing key if current value is equal
+// to old one, returns true if value is swapped.
+func CompareAndSwap[K any, V comparable](kv *Map[K, V], key K, old, val V) bool {
+	kv.detach()
+
+	el := kv.Find(key)
+	if el == nil || el.Value != old {
+		return false
+	}
+
+	kv.update(el, val)
+	return true
+}
+
+// Equal compares content of maps, values are compared using eq function
+func Equal[K any, V any](a, b *Map[K, V], eq func(V, V) bool) bool {
+	if a.length != b.length {
+		return false
+	}
+
+	x, y := a.head.Fingers[0], b.head.Fingers[0]
+	for x != nil && y != nil {
+		if a.compare(x.Key, y.Key) != 0 || !eq(x.Value, y.Value) {
+			return false
+		}
+		x, y = x.Fingers[0], y.Fingers[0]
+	}
+
+	return x == nil && y == nil
+}
+
+// insert new pair after the path
+func (kv *Map[K, V]) insert(path [L]*Pair[K, V], rank [L]int, key K, val V) *Pair[K, V] {
+	kv.finger.reset()
+	lvl, el := kv.CreatePair(L, key, val)
 
 	// re-bind fingers to new node
-	for level := 0; level < rank; level++ {
+	pos := rank[0] + 1
+	for level := 0; level < lvl; level++ {
 		el.Fingers[level] = path[level].Fingers[level]
+		el.spans[level] = rank[level] + path[level].spans[level] + 1 - pos
 		path[level].Fingers[level] = el
+		path[level].spans[level] = pos - rank[level]
+	}
+
+	// fingers above the node skips one more element
+	for level := lvl; level < L; level++ {
+		path[level].spans[level]++
 	}
 
 	kv.length++
-	return true, el
+
+	kv.observePut(key, *new(V), val, false)
+
+	return el
+}
+
+// update value of existing pair
+func (kv *Map[K, V]) update(el *Pair[K, V], val V) {
+	old := el.Value
+	el.Value = val
+
+	kv.observePut(el.Key, old, val, true)
+}
+
+// notify hook and watchers about put of the key
+func (kv *Map[K, V]) observePut(key K, old, val V, updated bool) {
+	if kv.onPut != nil {
+		kv.onPut(key, old, val, updated)
+	}
+
+	if kv.watch != nil {
+		kv.watch.put(key, old, val, updated)
+	}
+}
+
+// notify hook and watchers about removal of the key
+func (kv *Map[K, V]) observeCut(key K, val V) {
+	if kv.onCut != nil {
+		kv.onCut(key, val)
+	}
+
+	if kv.watch != nil {
+		kv.watch.cut(key, val)
+	}
+}
+
+// removals are observed by hook or watchers
+func (kv *Map[K, V]) observed() bool {
+	return kv.onCut != nil || kv.watch != nil
 }
 
 // creates a new node, randomly defines empty fingers (level of the node)
 func (kv *Map[K, V]) CreatePair(maxL int, key K, val V) (int, *Pair[K, V]) {
-	// See: https://golang.org/src/math/rand/rand.go#L150
-	p := float64(kv.random.Int63()) / (1 << 63)
-
 	level := 0
-	for level < maxL && p < kv.ptable[level] {
-		level++
+	if kv.geometric != nil && kv.hash == nil {
+		level = kv.geometric.level(maxL)
+	} else {
+		// See: https://golang.org/src/math/rand/rand.go#L150
+		p := float64(kv.random.Int63()) / (1 << 63)
+		if kv.hash != nil {
+			p = float64(kv.hash(key)>>1) / (1 << 63)
+		}
+
+		for level < maxL && p < kv.ptable[level] {
+			level++
+		}
 	}
 
 	node := kv.NewPair(key, level)
@@ -203,50 +653,269 @@ func (kv *Map[K, V]) CreatePair(maxL int, key K, val V) (int, *Pair[K, V]) {
 
 // allocate new pair
 func (kv *Map[K, V]) NewPair(key K, rank int) *Pair[K, V] {
+	if malloc, ok := kv.malloc.(NodeAllocator[K, Pair[K, V]]); ok {
+		el := malloc.AllocNode(key, rank)
+		if len(el.spans) < rank {
+			el.spans = make([]int, rank)
+		}
+		return el
+	}
+
 	if kv.malloc != nil {
-		return kv.malloc.Alloc(key)
+		el := kv.malloc.Alloc(key)
+		if len(el.spans) < rank {
+			el.spans = make([]int, rank)
+		}
+		return el
 	}
 
-	return &Pair[K, V]{Fingers: make([]*Pair[K, V], rank)}
+	return &Pair[K, V]{
+		Fingers: make([]*Pair[K, V], rank),
+		spans:   make([]int, rank),
+	}
 }
 
-// Check is element exists in set
-func (kv *Map[K, V]) Get(key K) (V, *Pair[K, V]) {
-	el, _ := kv.Skip(0, key)
+// release node to allocator
+func (kv *Map[K, V]) free(el *Pair[K, V]) {
+	if malloc, ok := kv.malloc.(NodeAllocator[K, Pair[K, V]]); ok {
+		malloc.FreeNode(el)
+		return
+	}
+
+	kv.malloc.Free(el.Key)
+}
 
-	if el != nil && el.Key == key {
-		return el.Value, el
+// Get value of the key
+func (kv *Map[K, V]) Get(key K) (V, bool) {
+	if el := kv.Find(key); el != nil {
+		return el.Value, true
 	}
 
-	return *new(V), nil
+	return *new(V), false
 }
 
-// Cut element from the set, returns true if element is removed
-func (kv *Map[K, V]) Cut(key K) (bool, *Pair[K, V]) {
+// Find pair of the key, returns nil if key does not exist
+func (kv *Map[K, V]) Find(key K) *Pair[K, V] {
+	if kv.probe != nil {
+		kv.probe.enter(probeGet)
+		defer kv.probe.exit()
+	}
+
+	var el *Pair[K, V]
+	if kv.finger != nil {
+		el = kv.seekFinger(key)
+	} else {
+		el, _ = kv.Skip(0, key)
+	}
+
+	if el != nil && kv.compare(el.Key, key) == 0 {
+		return el
+	}
+
+	return nil
+}
+
+// Cut key from the map, returns the value and true if key is removed
+func (kv *Map[K, V]) Cut(key K) (V, bool) {
+	kv.detach()
+
+	if kv.probe != nil {
+		kv.probe.enter(probeCut)
+		defer kv.probe.exit()
+	}
+
 	rank := L
 	v, path := kv.Skip(0, key)
 
-	if v == nil || v.Key != key {
-		return false, nil
+	if v == nil || kv.compare(v.Key, key) != 0 {
+		return *new(V), false
 	}
 
+	val := v.Value
+	kv.unlink(rank, path, v)
+
+	return val, true
+}
+
+// unlink node from the path
+func (kv *Map[K, V]) unlink(rank int, path [L]*Pair[K, V], v *Pair[K, V]) {
+	kv.finger.reset()
+
 	for level := 0; level < rank; level++ {
 		if path[level].Fingers[level] == v {
 			if len(v.Fingers) > level {
 				path[level].Fingers[level] = v.Fingers[level]
+				path[level].spans[level] += v.spans[level] - 1
 			} else {
 				path[level].Fingers[level] = nil
 			}
+		} else {
+			path[level].spans[level]--
 		}
 	}
 
 	kv.length--
 
+	kv.observeCut(v.Key, v.Value)
+
+	if kv.malloc != nil {
+		kv.free(v)
+	}
+}
+
+// evict pairs from the bounded end of the map while it exceeds capacity,
+// the pair keep (e.g. just inserted one) is never evicted
+func (kv *Map[K, V]) evict(keep *Pair[K, V]) {
+	if kv.bound == nil {
+		return
+	}
+
+	for kv.length > kv.bound.capacity {
+		el := kv.head.Fingers[0]
+		if el == keep {
+			el = el.Fingers[0]
+		}
+		if kv.bound.end == EvictMax {
+			el = kv.At(kv.length - 1)
+			if el == keep {
+				el = kv.At(kv.length - 2)
+			}
+		}
+
+		if el == nil {
+			return
+		}
+
+		key, val := el.Key, el.Value
+		_, path := kv.Skip(0, key)
+		kv.unlink(L, path, el)
+
+		if kv.bound.onEvict != nil {
+			kv.bound.onEvict(key, val)
+		}
+	}
+}
+
+// CutRange removes all keys in the interval [from, to),
+// returns number of removed keys
+func (kv *Map[K, V]) CutRange(from, to K) int {
+	kv.detach()
+
+	if kv.compare(from, to) >= 0 {
+		return 0
+	}
+
+	_, lo, lrank := kv.skipWithRank(from)
+	_, hi, hrank := kv.skipWithRank(to)
+
+	n := hrank[0] - lrank[0]
+	if n == 0 {
+		return 0
+	}
+
+	kv.finger.reset()
+
+	// path to upper bound passes removed pairs, they are released
+	// after the path is relinked
+	v := lo[0].Fingers[0]
+	for level := 0; level < L; level++ {
+		lo[level].spans[level] = hrank[level] + hi[level].spans[level] - lrank[level] - n
+		lo[level].Fingers[level] = hi[level].Fingers[level]
+	}
+
+	if kv.malloc != nil || kv.observed() {
+		for i := 0; i < n; i++ {
+			next := v.Fingers[0]
+			kv.observeCut(v.Key, v.Value)
+			if kv.malloc != nil {
+				kv.free(v)
+			}
+			v = next
+		}
+	}
+
+	kv.length -= n
+	return n
+}
+
+// sweep removes all pairs that satisfy the predicate in a single pass O(n),
+// fingers and spans of remaining pairs are rebuilt. Returns number of
+// removed pairs.
+func (kv *Map[K, V]) sweep(dead func(*Pair[K, V]) bool) int {
+	kv.detach()
+	kv.finger.reset()
+
+	tail := [L]*Pair[K, V]{}
+	rank := [L]int{}
+	for i := range tail {
+		tail[i] = kv.head
+	}
+
+	pos, n := 0, 0
+	for e := kv.head.Fingers[0]; e != nil; {
+		next := e.Fingers[0]
+
+		if dead(e) {
+			kv.observeCut(e.Key, e.Value)
+			if kv.malloc != nil {
+				kv.free(e)
+			}
+			e = next
+			n++
+			continue
+		}
+
+		pos++
+		for level := range e.Fingers {
+			tail[level].Fingers[level] = e
+			tail[level].spans[level] = pos - rank[level]
+			tail[level] = e
+			rank[level] = pos
+		}
+		e = next
+	}
+
+	for level := 0; level < L; level++ {
+		tail[level].Fingers[level] = nil
+		tail[level].spans[level] = 0
+	}
+
+	kv.length -= n
+	return n
+}
+
+// Clear removes all elements from the map, the configuration is retained.
+// Removed elements are returned to the allocator.
+func (kv *Map[K, V]) Clear() {
+	kv.finger.reset()
+
+	if kv.observed() {
+		for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+			kv.observeCut(e.Key, e.Value)
+		}
+	}
+
+	if kv.shared {
+		kv.head = &Pair[K, V]{Fingers: make([]*Pair[K, V], L), spans: make([]int, L)}
+		kv.length = 0
+		kv.shared = false
+		return
+	}
+
 	if kv.malloc != nil {
-		kv.malloc.Free(key)
+		for e := kv.head.Fingers[0]; e != nil; {
+			next := e.Fingers[0]
+			kv.free(e)
+			e = next
+		}
+	}
+
+	for level := 0; level < L; level++ {
+		kv.head.Fingers[level] = nil
+		kv.head.spans[level] = 0
 	}
 
-	return true, v
+	kv.length = 0
 }
 
 // Head of skiplist
@@ -259,70 +928,474 @@ func (kv *Map[K, V]) Values() *Pair[K, V] {
 	return kv.head.Fingers[0]
 }
 
+// Map elements on the level, the express lane of skip list
+//
+//	for e := kv.ValuesOn(level); e != nil; e = e.NextOn(level) { /* ... */}
+func (kv *Map[K, V]) ValuesOn(level int) *Pair[K, V] {
+	if level < 0 || level >= L {
+		return nil
+	}
+
+	return kv.head.Fingers[level]
+}
+
+// KeysSlice returns ordered slice of all keys
+func (kv *Map[K, V]) KeysSlice() []K {
+	seq := make([]K, 0, kv.length)
+	for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		seq = append(seq, e.Key)
+	}
+
+	return seq
+}
+
+// ValuesSlice returns slice of all values ordered by keys
+func (kv *Map[K, V]) ValuesSlice() []V {
+	seq := make([]V, 0, kv.length)
+	for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		seq = append(seq, e.Value)
+	}
+
+	return seq
+}
+
 // Successor elements from set
 func (kv *Map[K, V]) Successor(key K) *Pair[K, V] {
 	el, _ := kv.Skip(0, key)
 	return el
 }
 
+// At returns pair at position i, pairs are indexed from 0
+func (kv *Map[K, V]) At(i int) *Pair[K, V] {
+	if i < 0 || i >= kv.length {
+		return nil
+	}
+
+	pos := -1
+	node := kv.head
+	for lev := L - 1; lev >= 0; lev-- {
+		for node.Fingers[lev] != nil && pos+node.spans[lev] <= i {
+			pos += node.spans[lev]
+			node = node.Fingers[lev]
+		}
+
+		if pos == i {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// TopK returns k pairs with the greatest keys, in descending order.
+// It takes O(log n + k).
+func (kv *Map[K, V]) TopK(k int) []*Pair[K, V] {
+	k = max(min(k, kv.length), 0)
+	seq := make([]*Pair[K, V], k)
+
+	e := kv.At(kv.length - k)
+	for i := k - 1; i >= 0; i-- {
+		seq[i] = e
+		e = e.Fingers[0]
+	}
+
+	return seq
+}
+
+// BottomK returns k pairs with the smallest keys, in ascending order.
+// It takes O(k).
+func (kv *Map[K, V]) BottomK(k int) []*Pair[K, V] {
+	k = max(min(k, kv.length), 0)
+	seq := make([]*Pair[K, V], k)
+
+	e := kv.head.Fingers[0]
+	for i := 0; i < k; i++ {
+		seq[i] = e
+		e = e.Fingers[0]
+	}
+
+	return seq
+}
+
+// Sample returns n distinct pairs chosen uniformly at random, in key order.
+// Random ranks are resolved by At, it takes O(n log n).
+func (kv *Map[K, V]) Sample(n int) []*Pair[K, V] {
+	n = max(min(n, kv.length), 0)
+	random := rand.New(kv.random)
+
+	// Floyd's algorithm of sampling distinct ranks
+	ranks := make(map[int]struct{}, n)
+	for j := kv.length - n; j < kv.length; j++ {
+		t := random.Intn(j + 1)
+		if _, has := ranks[t]; has {
+			t = j
+		}
+		ranks[t] = struct{}{}
+	}
+
+	seq := make([]int, 0, n)
+	for i := range ranks {
+		seq = append(seq, i)
+	}
+	slices.Sort(seq)
+
+	pairs := make([]*Pair[K, V], n)
+	for i, rank := range seq {
+		pairs[i] = kv.At(rank)
+	}
+
+	return pairs
+}
+
+// SampleWeighted returns n pairs chosen at random with replacement, in key
+// order. The probability of the pair is proportional to its weight, pairs
+// with non-positive weight are never chosen. The weight is evaluated once
+// per pair, it takes O(len + n log n).
+func (kv *Map[K, V]) SampleWeighted(n int, weight func(K, V) float64) []*Pair[K, V] {
+	if n <= 0 {
+		return nil
+	}
+
+	// cumulative weights of pairs
+	var (
+		seq   []*Pair[K, V]
+		cum   []float64
+		total float64
+	)
+	for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		if w := weight(e.Key, e.Value); w > 0 {
+			total += w
+			seq = append(seq, e)
+			cum = append(cum, total)
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	random := rand.New(kv.random)
+	points := make([]float64, n)
+	for i := range points {
+		points[i] = random.Float64() * total
+	}
+	slices.Sort(points)
+
+	pairs := make([]*Pair[K, V], n)
+	at := 0
+	for i, x := range points {
+		for at < len(cum)-1 && x >= cum[at] {
+			at++
+		}
+		pairs[i] = seq[at]
+	}
+
+	return pairs
+}
+
+// RankOf returns position of the key in the map, pairs are indexed from 0.
+// If key is not found, it returns the position where key would be inserted.
+func (kv *Map[K, V]) RankOf(key K) (int, bool) {
+	el, _, rank := kv.skipWithRank(key)
+
+	if el != nil && kv.compare(el.Key, key) == 0 {
+		return rank[0], true
+	}
+
+	return rank[0], false
+}
+
+// CountRange returns number of keys in the interval [from, to)
+func (kv *Map[K, V]) CountRange(from, to K) int {
+	if kv.compare(from, to) >= 0 {
+		return 0
+	}
+
+	_, _, lo := kv.skipWithRank(from)
+	_, _, hi := kv.skipWithRank(to)
+
+	return hi[0] - lo[0]
+}
+
+// Seek jumps forward from the element to the first element that is greater
+// or equal to key. The jump uses fingers, it takes O(log d) where d is
+// a distance between elements.
+func (kv *Map[K, V]) Seek(el *Pair[K, V], key K) *Pair[K, V] {
+	if el == nil || kv.compare(el.Key, key) >= 0 {
+		return el
+	}
+
+	node, lev := el, 0
+	for {
+		for lev+1 < len(node.Fingers) && node.Fingers[lev+1] != nil && kv.compare(node.Fingers[lev+1].Key, key) < 0 {
+			lev++
+		}
+
+		if next := node.Fingers[lev]; next != nil && kv.compare(next.Key, key) < 0 {
+			node = next
+			continue
+		}
+
+		if lev == 0 {
+			return node.Fingers[0]
+		}
+		lev--
+	}
+}
+
 // Split set of elements by key
 func (kv *Map[K, V]) Split(key K) *Map[K, V] {
-	node, path := kv.Skip(0, key)
+	kv.detach()
+	kv.finger.reset()
 
+	_, path, rank := kv.skipWithRank(key)
+
+	head := &Pair[K, V]{Fingers: make([]*Pair[K, V], L), spans: make([]int, L)}
 	for level, x := range path {
+		head.Fingers[level] = x.Fingers[level]
+		head.spans[level] = rank[level] + x.spans[level] - rank[0]
 		x.Fingers[level] = nil
+		x.spans[level] = 0
 	}
 
-	head := &Pair[K, V]{Fingers: make([]*Pair[K, V], L)}
-
 	tail := &Map[K, V]{
-		head:   head,
-		null:   *new(K),
-		length: 0,
-		random: kv.random,
-		path:   [L]*Pair[K, V]{},
-		ptable: kv.ptable,
-		malloc: kv.malloc,
-	}
-	tail.head.Fingers[0] = node
-
-	length := 0
-	for n := node; n != nil; n = n.Fingers[0] {
-		length++
+		head:      head,
+		null:      *new(K),
+		length:    0,
+		random:    kv.random,
+		ptable:    kv.ptable,
+		malloc:    kv.malloc,
+		compare:   kv.ordering(),
+		desc:      kv.desc,
+		hash:      kv.hash,
+		geometric: kv.geometric,
+		bound:     kv.bound,
+		onPut:     kv.onPut,
+		onCut:     kv.onCut,
+		codec:     kv.codec,
 	}
 
+	length := kv.length - rank[0]
 	tail.length = length
 	kv.length -= length
 
+	if kv.finger != nil {
+		tail.finger = &finger[K, V]{}
+	}
+
 	return tail
 }
 
+// Clone makes a copy of the map, the copy has identical structure
+func (kv *Map[K, V]) Clone() *Map[K, V] {
+	head := &Pair[K, V]{Fingers: make([]*Pair[K, V], L), spans: make([]int, L)}
+	copy(head.spans, kv.head.spans)
+
+	clone := &Map[K, V]{
+		head:      head,
+		null:      *new(K),
+		length:    kv.length,
+		random:    kv.random,
+		ptable:    kv.ptable,
+		malloc:    kv.malloc,
+		compare:   kv.ordering(),
+		desc:      kv.desc,
+		hash:      kv.hash,
+		geometric: kv.geometric,
+		bound:     kv.bound,
+		onPut:     kv.onPut,
+		onCut:     kv.onCut,
+		codec:     kv.codec,
+	}
+
+	tail := [L]*Pair[K, V]{}
+	for i := range tail {
+		tail[i] = head
+	}
+
+	for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		el := clone.NewPair(e.Key, len(e.Fingers))
+		el.Key = e.Key
+		el.Value = e.Value
+		copy(el.spans, e.spans)
+
+		for level := range e.Fingers {
+			tail[level].Fingers[level] = el
+			tail[level] = el
+		}
+	}
+
+	if kv.finger != nil {
+		clone.finger = &finger[K, V]{}
+	}
+
+	return clone
+}
+
+// Join is inverse of Split, it appends the tail to the map.
+// Keys of the tail must be greater than keys of the map, otherwise
+// false is returned and both maps remain unchanged. The tail is empty
+// after the operation.
+func (kv *Map[K, V]) Join(tail *Map[K, V]) bool {
+	kv.detach()
+	tail.detach()
+	kv.finger.reset()
+	tail.finger.reset()
+
+	var (
+		path [L]*Pair[K, V]
+		rank [L]int
+	)
+
+	node, pos := kv.head, 0
+	for lev := L - 1; lev >= 0; lev-- {
+		for node.Fingers[lev] != nil {
+			pos += node.spans[lev]
+			node = node.Fingers[lev]
+		}
+		path[lev], rank[lev] = node, pos
+	}
+
+	first := tail.head.Fingers[0]
+	if first == nil {
+		return true
+	}
+
+	if path[0] != kv.head && kv.compare(path[0].Key, first.Key) >= 0 {
+		return false
+	}
+
+	for lev := 0; lev < L; lev++ {
+		if tail.head.Fingers[lev] != nil {
+			path[lev].Fingers[lev] = tail.head.Fingers[lev]
+			path[lev].spans[lev] = kv.length - rank[lev] + tail.head.spans[lev]
+		}
+		tail.head.Fingers[lev] = nil
+		tail.head.spans[lev] = 0
+	}
+
+	kv.length += tail.length
+	tail.length = 0
+
+	kv.evict(nil)
+	return true
+}
+
+// reverse ordering of keys
+func (kv *Map[K, V]) reverse() {
+	compare := kv.compare
+	kv.compare = func(a, b K) int { return compare(b, a) }
+	kv.desc = !kv.desc
+
+	if unprobed := kv.unprobed; unprobed != nil {
+		kv.unprobed = func(a, b K) int { return unprobed(b, a) }
+	}
+}
+
 // --------------------------------------------------------------------------------------
 
 // Configure Set properties
-type MapConfig[K Key, V any] func(*Map[K, V])
+type MapConfig[K any, V any] func(*Map[K, V])
 
 // Configure Random Generator
-func MapWithRandomSource[K Key, V any](random rand.Source) MapConfig[K, V] {
+func MapWithRandomSource[K any, V any](random rand.Source) MapConfig[K, V] {
 	return func(kv *Map[K, V]) {
 		kv.random = random
 	}
 }
 
+// Configure deterministic levels of nodes, the level is derived from hash
+// of the key. The structure depends on the keys only, identical keys produce
+// identical structure regardless of the order of insertion.
+func MapWithDeterministicLevels[K Key, V any]() MapConfig[K, V] {
+	return MapWithHash[K, V](hashOf[K])
+}
+
+// Configure hash of keys, levels of nodes are derived from the hash
+// (see MapWithDeterministicLevels)
+func MapWithHash[K any, V any](hash func(K) uint64) MapConfig[K, V] {
+	return func(kv *Map[K, V]) {
+		kv.hash = hash
+	}
+}
+
+// Configure fast generator of levels, the level is drawn from trailing zero
+// bits of xorshift generator, each level takes 1/2ⁿ of nodes below (p = 2⁻ⁿ).
+// It avoids float arithmetic and the scan of probability table.
+func MapWithGeometricLevels[K any, V any](n int) MapConfig[K, V] {
+	return func(kv *Map[K, V]) {
+		MapWithProbability[K, V](math.Pow(2, -float64(n)))(kv)
+		kv.geometric = newGeometric(n, uint64(time.Now().UnixNano()))
+	}
+}
+
+// Configure finger search, the map remembers the path to the last accessed
+// key and starts the search of greater keys from it, it takes O(log d) where
+// d is a distance between keys. Put moves the finger, Get only reads it.
+func MapWithFingerSearch[K any, V any]() MapConfig[K, V] {
+	return func(kv *Map[K, V]) {
+		kv.finger = &finger[K, V]{}
+	}
+}
+
+// Configure instrumentation of Get, Put and Cut, the probe counts
+// comparisons of keys and hops between nodes.
+func MapWithProbe[K any, V any](probe *Probe) MapConfig[K, V] {
+	return func(kv *Map[K, V]) {
+		kv.probe = probe
+		kv.unprobed = kv.compare
+		kv.compare = probed(probe, kv.compare)
+	}
+}
+
+// Configure capacity of the map, inserts beyond the capacity evict pairs
+// from the given end of the map and invoke onEvict callback (if defined).
+// Bulk operations (PutAll, Merge) evict once all pairs are inserted.
+func MapWithCapacity[K any, V any](capacity int, end Evict, onEvict func(K, V)) MapConfig[K, V] {
+	return func(kv *Map[K, V]) {
+		kv.bound = &bound[K, V]{capacity: capacity, end: end, onEvict: onEvict}
+	}
+}
+
+// Configure hook invoked when value of the key is put into the map, updated
+// is true if the key exists, old is the replaced value. The hook is invoked
+// synchronously, it must not mutate the map.
+func MapWithOnPut[K any, V any](hook func(key K, old, val V, updated bool)) MapConfig[K, V] {
+	return func(kv *Map[K, V]) {
+		kv.onPut = hook
+	}
+}
+
+// Configure hook invoked when the key is removed from the map, including
+// eviction and Clear. The hook is invoked synchronously, it must not mutate
+// the map.
+func MapWithOnCut[K any, V any](hook func(key K, val V)) MapConfig[K, V] {
+	return func(kv *Map[K, V]) {
+		kv.onCut = hook
+	}
+}
+
 // Configure Memory Allocator
-func MapWithAllocator[K Key, V any](malloc Allocator[K, Pair[K, V]]) MapConfig[K, V] {
+func MapWithAllocator[K any, V any](malloc Allocator[K, Pair[K, V]]) MapConfig[K, V] {
 	return func(kv *Map[K, V]) {
 		kv.malloc = malloc
 	}
 }
 
+// Configure descending ordering of keys
+func MapWithDescending[K any, V any]() MapConfig[K, V] {
+	return func(kv *Map[K, V]) {
+		kv.reverse()
+	}
+}
+
 // Configure Probability table
 // Use math.Log(B)/B < p < math.Pow(B, -0.5)
 //
 // The probability help to control the "distance" between elements on each level
 // Use p = math.Pow(B, -0.5), where B is number of elements
 // On L1 distance is √B, L2 distance is B, Ln distance is (√B)ⁿ
-func MapWithProbability[K Key, V any](p float64) MapConfig[K, V] {
+func MapWithProbability[K any, V any](p float64) MapConfig[K, V] {
 	return func(kv *Map[K, V]) {
 		var ptable [L]float64
 
@@ -335,6 +1408,13 @@ func MapWithProbability[K Key, V any](p float64) MapConfig[K, V] {
 }
 
 // Configure Probability table so that each level takes (√B)ⁿ elements
-func MapWithBlockSize[K Key, V any](b int) MapConfig[K, V] {
+func MapWithBlockSize[K any, V any](b int) MapConfig[K, V] {
 	return MapWithProbability[K, V](math.Pow(float64(b), -0.5))
 }
+
+// Configure Codec of values used by serialization (binary and gob)
+func MapWithCodec[K any, V any](codec Codec[V]) MapConfig[K, V] {
+	return func(kv *Map[K, V]) {
+		kv.codec = codec
+	}
+}
@@ -9,14 +9,17 @@
 package skiplist_test
 
 import (
+	"fmt"
 	"math/rand"
 	"sort"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/fogfish/it/v2"
 	"github.com/fogfish/skiplist"
+	"github.com/fogfish/skiplist/ord"
 )
 
 // ---------------------------------------------------------------
@@ -36,14 +39,12 @@ func MapSuite[K skiplist.Key](t *testing.T, seq []K) {
 	)
 
 	t.Run("Put", func(t *testing.T) {
-		f := func(has bool, node *skiplist.Pair[K, K]) bool { return has }
-
 		for _, el := range seq {
 			it.Then(t).Should(
-				it.True(f(kv.Put(el, el))),
+				it.True(kv.Put(el, el)),
 			).ShouldNot(
-				it.True(f(kv.Put(el, *new(K)))),
-				it.True(f(kv.Put(el, el))),
+				it.True(kv.Put(el, *new(K))),
+				it.True(kv.Put(el, el)),
 			)
 		}
 
@@ -53,16 +54,109 @@ func MapSuite[K skiplist.Key](t *testing.T, seq []K) {
 		)
 	})
 
+	t.Run("PutAll", func(t *testing.T) {
+		other := skiplist.NewMap[K, K]()
+
+		pairs := make([]skiplist.Pair[K, K], len(sorted))
+		for i, x := range sorted {
+			pairs[i] = skiplist.Pair[K, K]{Key: x, Value: x}
+		}
+
+		it.Then(t).Should(
+			it.Equal(other.PutAll(pairs...), len(seq)),
+			it.Equal(other.PutAll(pairs...), 0),
+			it.Equal(other.Length(), len(seq)),
+		)
+
+		values := other.Values()
+		for i := 0; i < len(sorted); i++ {
+			it.Then(t).Should(
+				it.Equal(values.Key, sorted[i]),
+				it.Equal(values.Value, sorted[i]),
+				it.Equal(other.At(i).Key, sorted[i]),
+			)
+			values = values.Next()
+		}
+	})
+
+	t.Run("GetOrInsert", func(t *testing.T) {
+		f := func(val K, has bool) bool { return has }
+
+		for _, el := range seq {
+			val, has := kv.GetOrInsert(el, *new(K))
+			it.Then(t).Should(
+				it.True(has),
+				it.Equal(val, el),
+				it.Equal(kv.Find(el).Key, el),
+				it.True(f(kv.GetOrCompute(el, func() K { panic("not expected") }))),
+			)
+		}
+
+		other := skiplist.NewMap[K, K]()
+		for _, el := range seq {
+			it.Then(t).ShouldNot(
+				it.True(f(other.GetOrCompute(el, func() K { return el }))),
+			)
+
+			val, _ := other.Get(el)
+			it.Then(t).Should(
+				it.Equal(val, el),
+			)
+		}
+
+		it.Then(t).Should(
+			it.Equal(other.Length(), len(seq)),
+		)
+	})
+
 	t.Run("Get", func(t *testing.T) {
 		for _, el := range seq {
-			val, node := kv.Get(el)
+			val, has := kv.Get(el)
 			it.Then(t).Should(
-				it.True(node != nil),
+				it.True(has),
 				it.Equal(val, el),
 			)
 		}
 	})
 
+	t.Run("Compute", func(t *testing.T) {
+		other := skiplist.NewMap[K, int]()
+		incr := func(v int, _ bool) (int, bool) { return v + 1, true }
+		decr := func(v int, _ bool) (int, bool) { return v - 1, v > 1 }
+
+		for _, el := range seq {
+			a, hasA := other.Compute(el, incr)
+			b, hasB := other.Compute(el, incr)
+			it.Then(t).Should(
+				it.True(hasA),
+				it.True(hasB),
+				it.Equal(a, 1),
+				it.Equal(b, 2),
+			)
+		}
+
+		it.Then(t).Should(
+			it.Equal(other.Length(), len(seq)),
+		)
+
+		for _, el := range seq {
+			a, hasA := other.Compute(el, decr)
+			_, hasB := other.Compute(el, decr)
+			_, hasC := other.Compute(el, decr)
+			it.Then(t).Should(
+				it.True(hasA),
+				it.Equal(a, 1),
+			).ShouldNot(
+				it.True(hasB),
+				it.True(hasC),
+			)
+		}
+
+		it.Then(t).Should(
+			it.Equal(other.Length(), 0),
+		)
+	})
+
 	t.Run("Head", func(t *testing.T) {
 		it.Then(t).ShouldNot(
 			it.Nil(kv.Head()),
@@ -72,9 +166,9 @@ func MapSuite[K skiplist.Key](t *testing.T, seq []K) {
 	t.Run("Values", func(t *testing.T) {
 		values := kv.Values()
 		for i := 0; i < len(sorted); i++ {
-			val, node := kv.Get(values.Key)
+			val, has := kv.Get(values.Key)
 			it.Then(t).Should(
-				it.True(node != nil),
+				it.True(has),
 				it.Equal(val, sorted[i]),
 				it.Equal(values.Key, sorted[i]),
 				it.Equal(values.Value, sorted[i]),
@@ -83,12 +177,19 @@ func MapSuite[K skiplist.Key](t *testing.T, seq []K) {
 		}
 	})
 
+	t.Run("KeysSlice", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Seq(kv.KeysSlice()).Equal(sorted...),
+			it.Seq(kv.ValuesSlice()).Equal(sorted...),
+		)
+	})
+
 	t.Run("Values.NextOn", func(t *testing.T) {
 		values := kv.Values()
 		for i := 0; i < len(sorted); i++ {
-			val, node := kv.Get(values.Key)
+			val, has := kv.Get(values.Key)
 			it.Then(t).Should(
-				it.True(node != nil),
+				it.True(has),
 				it.Equal(val, sorted[i]),
 				it.Equal(values.Key, sorted[i]),
 				it.Equal(values.Value, sorted[i]),
@@ -101,9 +202,9 @@ func MapSuite[K skiplist.Key](t *testing.T, seq []K) {
 		for _, k := range []int{0, len(sorted) / 4, len(sorted) / 2, len(sorted) - 1} {
 			values := kv.Successor(sorted[k])
 			for i := k; i < len(sorted); i++ {
-				val, node := kv.Get(values.Key)
+				val, has := kv.Get(values.Key)
 				it.Then(t).Should(
-					it.True(node != nil),
+					it.True(has),
 					it.Equal(val, sorted[i]),
 					it.Equal(values.Key, sorted[i]),
 				)
@@ -118,21 +219,115 @@ func MapSuite[K skiplist.Key](t *testing.T, seq []K) {
 		)
 	})
 
+	t.Run("At", func(t *testing.T) {
+		for i := 0; i < len(sorted); i++ {
+			it.Then(t).Should(
+				it.Equal(kv.At(i).Key, sorted[i]),
+			)
+		}
+
+		it.Then(t).Should(
+			it.True(kv.At(-1) == nil),
+			it.True(kv.At(len(sorted)) == nil),
+		)
+	})
+
+	t.Run("RankOf", func(t *testing.T) {
+		for i := 0; i < len(sorted); i++ {
+			rank, has := kv.RankOf(sorted[i])
+			it.Then(t).Should(
+				it.True(has),
+				it.Equal(rank, i),
+			)
+		}
+	})
+
+	t.Run("CountRange", func(t *testing.T) {
+		for _, k := range [][]int{
+			{0, 0},
+			{0, len(sorted) / 4},
+			{len(sorted) / 4, len(sorted) / 2},
+			{len(sorted) / 2, len(sorted) - 1},
+			{0, len(sorted) - 1},
+		} {
+			it.Then(t).Should(
+				it.Equal(kv.CountRange(sorted[k[0]], sorted[k[1]]), k[1]-k[0]),
+				it.Equal(kv.CountRange(sorted[k[1]], sorted[k[0]]), 0),
+			)
+		}
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		clone := kv.Clone()
+		clone.Cut(sorted[0])
+
+		_, has := kv.Get(sorted[0])
+		it.Then(t).Should(
+			it.True(has),
+			it.Equal(kv.Length(), len(sorted)),
+			it.Equal(clone.Length(), len(sorted)-1),
+		)
+
+		values := clone.Values()
+		for i := 1; i < len(sorted); i++ {
+			it.Then(t).Should(
+				it.Equal(values.Key, sorted[i]),
+				it.Equal(values.Value, sorted[i]),
+				it.Equal(clone.At(i-1).Key, sorted[i]),
+			)
+			values = values.Next()
+		}
+	})
+
 	t.Run("Cut", func(t *testing.T) {
 		for _, el := range seq {
-			val, node := kv.Cut(el)
+			val, has := kv.Cut(el)
 			_, exist := kv.Cut(el)
 			it.Then(t).Should(
-				it.True(val),
-				it.Equal(el, node.Value),
+				it.True(has),
+				it.Equal(el, val),
 			).ShouldNot(
-				it.True(exist != nil),
+				it.True(exist),
 			)
 		}
 
 		it.Then(t).Should(it.Equal(kv.Length(), 0))
 	})
 
+	t.Run("CutRange", func(t *testing.T) {
+		for _, k := range [][]int{
+			{0, 0},
+			{0, len(sorted) / 4},
+			{len(sorted) / 4, len(sorted) / 2},
+			{len(sorted) / 2, len(sorted) - 1},
+			{0, len(sorted) - 1},
+		} {
+			head := skiplist.NewMap[K, K]()
+			for _, x := range seq {
+				head.Put(x, x)
+			}
+
+			it.Then(t).Should(
+				it.Equal(head.CutRange(sorted[k[0]], sorted[k[1]]), k[1]-k[0]),
+				it.Equal(head.Length(), len(sorted)-k[1]+k[0]),
+			)
+
+			i, pos := 0, 0
+			for e := head.Values(); e != nil; e = e.Next() {
+				if i == k[0] {
+					i = k[1]
+				}
+				it.Then(t).Should(
+					it.Equal(e.Key, sorted[i]),
+					it.Equal(head.At(pos).Key, sorted[i]),
+				)
+				i++
+				pos++
+			}
+			it.Then(t).Should(it.Equal(i, len(sorted)))
+		}
+	})
+
 	t.Run("Split", func(t *testing.T) {
 		for _, k := range []int{0, len(sorted) / 4, len(sorted) / 2, len(sorted) - 1} {
 			head := skiplist.NewMap[K, K]()
@@ -143,34 +338,530 @@ func MapSuite[K skiplist.Key](t *testing.T, seq []K) {
 
 			hval := head.Values()
 			for i := 0; i < k; i++ {
-				val, node := head.Get(hval.Key)
+				val, has := head.Get(hval.Key)
 				_, exist := tail.Get(hval.Key)
 				it.Then(t).Should(
-					it.True(node != nil),
+					it.True(has),
 					it.Equal(val, sorted[i]),
 					it.Equal(hval.Key, sorted[i]),
+					it.Equal(head.At(i).Key, sorted[i]),
 				).ShouldNot(
-					it.True(exist != nil),
+					it.True(exist),
 				)
 				hval = hval.Next()
 			}
 
 			tval := tail.Values()
 			for i := k; i < len(sorted); i++ {
-				val, node := tail.Get(tval.Key)
+				val, has := tail.Get(tval.Key)
 				_, exist := head.Get(tval.Key)
 				it.Then(t).Should(
-					it.True(node != nil),
+					it.True(has),
 					it.Equal(val, sorted[i]),
 					it.Equal(tval.Key, sorted[i]),
+					it.Equal(tail.At(i-k).Key, sorted[i]),
 				).ShouldNot(
-					it.True(exist != nil),
+					it.True(exist),
 				)
 				tval = tval.Next()
 			}
 		}
 	})
 
+	t.Run("Join", func(t *testing.T) {
+		for _, k := range []int{0, len(sorted) / 4, len(sorted) / 2, len(sorted) - 1} {
+			head := skiplist.NewMap[K, K]()
+			for _, x := range seq {
+				head.Put(x, x)
+			}
+			tail := head.Split(sorted[k])
+
+			if k != 0 {
+				it.Then(t).ShouldNot(
+					it.True(tail.Join(head)),
+				)
+			}
+
+			it.Then(t).Should(
+				it.True(head.Join(tail)),
+				it.Equal(head.Length(), len(sorted)),
+				it.Equal(tail.Length(), 0),
+			)
+
+			values := head.Values()
+			for i := 0; i < len(sorted); i++ {
+				rank, _ := head.RankOf(sorted[i])
+				it.Then(t).Should(
+					it.Equal(values.Key, sorted[i]),
+					it.Equal(head.At(i).Key, sorted[i]),
+					it.Equal(rank, i),
+				)
+				values = values.Next()
+			}
+		}
+	})
+}
+
+func TestMapCompareAndSwap(t *testing.T) {
+	kv := skiplist.NewMap[int, string]()
+	kv.Put(1, "a")
+
+	it.Then(t).Should(
+		it.True(skiplist.CompareAndSwap(kv, 1, "a", "b")),
+	).ShouldNot(
+		it.True(skiplist.CompareAndSwap(kv, 1, "a", "c")),
+		it.True(skiplist.CompareAndSwap(kv, 2, "", "c")),
+	)
+
+	val, _ := kv.Get(1)
+	it.Then(t).Should(
+		it.Equal(val, "b"),
+		it.Equal(kv.Length(), 1),
+	)
+}
+
+func TestMapMerge(t *testing.T) {
+	a := skiplist.NewMap[int, int]()
+	b := skiplist.NewMap[int, int]()
+	for i := 0; i < 100; i++ {
+		a.Put(2*i, i)
+		b.Put(3*i, i)
+	}
+
+	a.Merge(b, func(k, x, y int) int { return -k })
+
+	it.Then(t).Should(
+		it.Equal(a.Length(), 166),
+		it.Equal(b.Length(), 100),
+	)
+
+	for i, e := 0, a.Values(); e != nil; i, e = i+1, e.Next() {
+		switch {
+		case e.Key%6 == 0 && e.Key < 200:
+			it.Then(t).Should(it.Equal(e.Value, -e.Key))
+		case e.Key%2 == 0 && e.Key < 200:
+			it.Then(t).Should(it.Equal(e.Value, e.Key/2))
+		default:
+			it.Then(t).Should(it.Equal(e.Value, e.Key/3))
+		}
+
+		it.Then(t).Should(
+			it.Equal(a.At(i).Key, e.Key),
+		)
+	}
+
+	a.Merge(b, nil)
+	val, _ := a.Get(6)
+	it.Then(t).Should(
+		it.Equal(val, 2),
+	)
+}
+
+func TestMapEqual(t *testing.T) {
+	eq := func(a, b string) bool { return a == b }
+
+	a := skiplist.NewMap[int, string]()
+	a.Put(1, "a")
+	a.Put(2, "b")
+
+	b := skiplist.NewMap[int, string]()
+	b.Put(2, "b")
+	b.Put(1, "a")
+
+	c := a.Clone()
+	c.Put(2, "c")
+
+	it.Then(t).Should(
+		it.True(skiplist.Equal(a, b, eq)),
+	).ShouldNot(
+		it.True(skiplist.Equal(a, c, eq)),
+		it.True(skiplist.Equal(a, skiplist.NewMap[int, string](), eq)),
+	)
+}
+
+func TestMapClear(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	kv.Put(1, 1)
+	kv.Put(2, 2)
+	kv.Clear()
+
+	it.Then(t).Should(
+		it.Equal(kv.Length(), 0),
+		it.True(kv.Values() == nil),
+	)
+
+	kv.Put(2, 2)
+	kv.Put(1, 1)
+	it.Then(t).Should(
+		it.Equal(kv.Length(), 2),
+		it.Equal(kv.At(0).Key, 1),
+	)
+}
+
+func TestMapWith(t *testing.T) {
+	type ID struct{ Seq, Node int }
+
+	kv := skiplist.NewMapWith[ID, string](
+		ord.From[ID](func(a, b ID) int {
+			if c := ord.Int.Compare(a.Seq, b.Seq); c != 0 {
+				return c
+			}
+			return ord.Int.Compare(a.Node, b.Node)
+		}),
+	)
+
+	kv.Put(ID{2, 1}, "c")
+	kv.Put(ID{1, 2}, "b")
+	kv.Put(ID{1, 1}, "a")
+	kv.Put(ID{1, 1}, "a")
+
+	val, has := kv.Get(ID{1, 2})
+	it.Then(t).Should(
+		it.Equal(kv.Length(), 3),
+		it.True(has),
+		it.Equal(val, "b"),
+		it.Seq(kv.ValuesSlice()).Equal("a", "b", "c"),
+	)
+
+	kv.Cut(ID{1, 2})
+	_, has = kv.Get(ID{1, 2})
+	it.Then(t).Should(
+		it.True(!has),
+		it.Seq(kv.ValuesSlice()).Equal("a", "c"),
+	)
+}
+
+func TestMapOfBytes(t *testing.T) {
+	kv := skiplist.NewMapWith[[]byte, int](ord.Bytes)
+	kv.Put([]byte{0x02}, 3)
+	kv.Put([]byte{0x01, 0xff}, 2)
+	kv.Put([]byte{0x01}, 1)
+
+	val, has := kv.Get([]byte{0x01, 0xff})
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, 2),
+		it.Seq(kv.ValuesSlice()).Equal(1, 2, 3),
+		it.Equal(kv.Successor([]byte{0x01, 0x00}).Value, 2),
+	)
+}
+
+func TestMapDescending(t *testing.T) {
+	kv := skiplist.NewMap(skiplist.MapWithDescending[int, int]())
+	for i := 0; i < 10; i++ {
+		kv.Put(i, i)
+	}
+
+	rev := skiplist.NewMapWith[int, int](ord.Reverse[int](ord.Int))
+	for i := 0; i < 10; i++ {
+		rev.Put(i, i)
+	}
+
+	it.Then(t).Should(
+		it.Seq(kv.KeysSlice()).Equal(9, 8, 7, 6, 5, 4, 3, 2, 1, 0),
+		it.Seq(rev.KeysSlice()).Equal(9, 8, 7, 6, 5, 4, 3, 2, 1, 0),
+		it.Equal(kv.Successor(5).Key, 5),
+		it.Equal(kv.CountRange(8, 2), 6),
+	)
+}
+
+func TestMapFromSorted(t *testing.T) {
+	pairs := make([]skiplist.Pair[int, int], 1000)
+	for i := 0; i < len(pairs); i++ {
+		pairs[i] = skiplist.Pair[int, int]{Key: 2 * i, Value: i}
+	}
+
+	kv := skiplist.NewMapFromSorted(pairs)
+	it.Then(t).Should(
+		it.Equal(kv.Length(), len(pairs)),
+		it.Greater(kv.Level(), 0),
+	)
+
+	for i := 0; i < len(pairs); i++ {
+		val, has := kv.Get(2 * i)
+		rank, _ := kv.RankOf(2 * i)
+		it.Then(t).Should(
+			it.True(has),
+			it.Equal(val, i),
+			it.Equal(rank, i),
+			it.Equal(kv.At(i).Key, 2*i),
+		)
+	}
+
+	kv.Put(1, -1)
+	kv.Cut(2)
+	it.Then(t).Should(
+		it.Equal(kv.Length(), len(pairs)),
+		it.Equal(kv.At(1).Key, 1),
+		it.Equal(kv.At(2).Key, 4),
+	)
+}
+
+func TestMapDeterministicLevels(t *testing.T) {
+	a := skiplist.NewMap(skiplist.MapWithDeterministicLevels[int, int]())
+	b := skiplist.NewMap(skiplist.MapWithDeterministicLevels[int, int]())
+
+	keys := rand.Perm(1000)
+	for _, key := range keys {
+		a.Put(key, key)
+	}
+	for key := len(keys) - 1; key >= 0; key-- {
+		b.Put(key, key)
+	}
+
+	x, y := a.Values(), b.Values()
+	for x != nil && y != nil {
+		it.Then(t).Should(
+			it.Equal(x.Key, y.Key),
+			it.Equal(x.Rank(), y.Rank()),
+		)
+		x, y = x.Next(), y.Next()
+	}
+
+	it.Then(t).Should(
+		it.True(x == nil && y == nil),
+		it.Equal(a.Level(), b.Level()),
+		it.Greater(a.Level(), 2),
+	)
+}
+
+func TestMapGeometricLevels(t *testing.T) {
+	kv := skiplist.NewMap(skiplist.MapWithGeometricLevels[int, int](1))
+	for i := 0; i < 10000; i++ {
+		kv.Put(rand.Intn(1000000), i)
+	}
+
+	n, rank := 0, 0
+	for e := kv.Values(); e != nil; e = e.Next() {
+		if e.Rank() > 1 {
+			rank++
+		}
+		n++
+	}
+
+	it.Then(t).Should(
+		it.Equal(n, kv.Length()),
+		it.Greater(rank, n*2/5),
+		it.Less(rank, n*3/5),
+	)
+}
+
+func TestMapFingerSearch(t *testing.T) {
+	kv := skiplist.NewMap(skiplist.MapWithFingerSearch[int, int]())
+	ref := skiplist.NewMap[int, int]()
+
+	for i := 0; i < 10000; i++ {
+		key := i/4 + rand.Intn(64)
+		switch rand.Intn(8) {
+		case 0:
+			kv.Cut(key)
+			ref.Cut(key)
+		case 1:
+			a, _ := kv.Get(key)
+			b, _ := ref.Get(key)
+			it.Then(t).Should(it.Equal(a, b))
+		case 2:
+			kv.CutRange(key, key+8)
+			ref.CutRange(key, key+8)
+		default:
+			kv.Put(key, i)
+			ref.Put(key, i)
+		}
+	}
+
+	it.Then(t).Should(
+		it.Equal(kv.Length(), ref.Length()),
+		it.True(skiplist.Equal(kv, ref, func(a, b int) bool { return a == b })),
+	)
+
+	for i := 0; i < kv.Length(); i++ {
+		rank, has := kv.RankOf(kv.At(i).Key)
+		it.Then(t).Should(it.True(has), it.Equal(rank, i))
+	}
+
+	clone := kv.Clone()
+	for i := 0; i < 1000; i++ {
+		clone.Put(20000+i, i)
+	}
+	it.Then(t).Should(
+		it.Equal(clone.At(clone.Length()-1).Key, 20999),
+		it.Equal(clone.Length(), kv.Length()+1000),
+	)
+}
+
+func TestMapFunc(t *testing.T) {
+	kv := skiplist.NewMapFunc[int, string](func(a, b int) int { return b - a })
+	kv.Put(1, "a")
+	kv.Put(3, "c")
+	kv.Put(2, "b")
+
+	set := skiplist.NewSetFunc[int](func(a, b int) int { return b - a })
+	set.AddAll(1, 3, 2)
+
+	it.Then(t).Should(
+		it.Seq(kv.KeysSlice()).Equal(3, 2, 1),
+		it.Equal(set.At(0).Key, 3),
+	)
+}
+
+func TestMapCapacity(t *testing.T) {
+	evicted := []int{}
+	onEvict := func(k int, v string) { evicted = append(evicted, k) }
+
+	kv := skiplist.NewMap(skiplist.MapWithCapacity(3, skiplist.EvictMin, onEvict))
+	for _, k := range []int{5, 3, 7, 1, 9} {
+		kv.Put(k, "")
+	}
+	it.Then(t).Should(
+		it.Equal(kv.Length(), 3),
+		it.Seq(kv.KeysSlice()).Equal(5, 7, 9),
+		it.Seq(evicted).Equal(3, 1),
+	)
+
+	// the inserted pair is never evicted by itself
+	evicted = evicted[:0]
+	val, has := kv.GetOrInsert(0, "new")
+	it.Then(t).Should(
+		it.Equal(val, "new"),
+		it.Equal(has, false),
+		it.Equal(kv.Length(), 3),
+		it.Seq(kv.KeysSlice()).Equal(0, 7, 9),
+		it.Seq(evicted).Equal(5),
+	)
+
+	evicted = evicted[:0]
+	kv = skiplist.NewMap(skiplist.MapWithCapacity(3, skiplist.EvictMax, onEvict))
+	kv.PutAll(
+		skiplist.Pair[int, string]{Key: 1},
+		skiplist.Pair[int, string]{Key: 2},
+		skiplist.Pair[int, string]{Key: 3},
+		skiplist.Pair[int, string]{Key: 4},
+		skiplist.Pair[int, string]{Key: 5},
+	)
+	kv.GetOrInsert(0, "")
+	it.Then(t).Should(
+		it.Equal(kv.Length(), 3),
+		it.Seq(kv.KeysSlice()).Equal(0, 1, 2),
+		it.Seq(evicted).Equal(5, 4, 3),
+	)
+}
+
+func TestMapTopK(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	for i := 0; i < 100; i++ {
+		kv.Put(i, i)
+	}
+
+	keys := func(seq []*skiplist.Pair[int, int]) []int {
+		out := make([]int, len(seq))
+		for i, e := range seq {
+			out[i] = e.Key
+		}
+		return out
+	}
+
+	it.Then(t).Should(
+		it.Seq(keys(kv.TopK(3))).Equal(99, 98, 97),
+		it.Seq(keys(kv.BottomK(3))).Equal(0, 1, 2),
+		it.Equal(len(kv.TopK(200)), 100),
+		it.Equal(len(kv.BottomK(200)), 100),
+		it.Equal(len(kv.TopK(0)), 0),
+		it.Equal(len(kv.BottomK(-1)), 0),
+	)
+}
+
+func TestMapSample(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	for i := 0; i < 100; i++ {
+		kv.Put(i, i)
+	}
+
+	hits := make([]int, 100)
+	for i := 0; i < 1000; i++ {
+		seq := kv.Sample(10)
+		it.Then(t).Should(it.Equal(len(seq), 10))
+
+		for j, e := range seq {
+			if j > 0 {
+				it.Then(t).Should(it.Less(seq[j-1].Key, e.Key))
+			}
+			hits[e.Key]++
+		}
+	}
+
+	for _, n := range hits {
+		it.Then(t).Should(it.Greater(n, 50), it.Less(n, 150))
+	}
+
+	it.Then(t).Should(
+		it.Equal(len(kv.Sample(200)), 100),
+		it.Equal(len(kv.Sample(0)), 0),
+	)
+}
+
+func TestMapSampleWeighted(t *testing.T) {
+	kv := skiplist.NewMap[int, float64]()
+	kv.Put(1, 1.0)
+	kv.Put(2, 0.0)
+	kv.Put(3, 3.0)
+	kv.Put(4, -1.0)
+
+	weight := func(k int, v float64) float64 { return v }
+
+	hits := map[int]int{}
+	seq := kv.SampleWeighted(4000, weight)
+	for i, e := range seq {
+		if i > 0 {
+			it.Then(t).Should(it.LessOrEqual(seq[i-1].Key, e.Key))
+		}
+		hits[e.Key]++
+	}
+
+	it.Then(t).Should(
+		it.Equal(len(seq), 4000),
+		it.Equal(hits[2], 0),
+		it.Equal(hits[4], 0),
+		it.Greater(hits[1], 800),
+		it.Less(hits[1], 1200),
+		it.Equal(hits[1]+hits[3], 4000),
+		it.True(kv.SampleWeighted(0, weight) == nil),
+		it.True(kv.SampleWeighted(10, func(int, float64) float64 { return 0 }) == nil),
+	)
+}
+
+func TestMapHooks(t *testing.T) {
+	log := []string{}
+	onPut := func(k int, old, val string, updated bool) {
+		log = append(log, fmt.Sprintf("put %d %s->%s %v", k, old, val, updated))
+	}
+	onCut := func(k int, val string) {
+		log = append(log, fmt.Sprintf("cut %d %s", k, val))
+	}
+
+	kv := skiplist.NewMap(
+		skiplist.MapWithOnPut(onPut),
+		skiplist.MapWithOnCut(onCut),
+	)
+	kv.Put(1, "a")
+	kv.Put(1, "b")
+	kv.Put(2, "c")
+	kv.Put(3, "d")
+	kv.Compute(2, func(v string, has bool) (string, bool) { return "", false })
+	kv.Cut(1)
+	kv.Cut(5)
+	kv.Clear()
+
+	it.Then(t).Should(
+		it.Seq(log).Equal(
+			"put 1 ->a false",
+			"put 1 a->b true",
+			"put 2 ->c false",
+			"put 3 ->d false",
+			"cut 2 c",
+			"cut 1 b",
+			"cut 3 d",
+		),
+	)
 }
 
 func TestMapOfIntPutGetCut(t *testing.T) {
@@ -220,6 +911,17 @@ func MapBench[K skiplist.Key](b *testing.B, gen func(int) K) {
 		}
 	})
 
+	b.Run("PutToTailFinger", func(b *testing.B) {
+		kv := skiplist.NewMap(skiplist.MapWithFingerSearch[K, K]())
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			key := gen(n)
+			kv.Put(key, key)
+		}
+	})
+
 	b.Run("PutToHead", func(b *testing.B) {
 		kv := skiplist.NewMap[K, K]()
 
@@ -242,6 +944,17 @@ func MapBench[K skiplist.Key](b *testing.B, gen func(int) K) {
 		}
 	})
 
+	b.Run("PutToRandGeometric", func(b *testing.B) {
+		kv := skiplist.NewMap(skiplist.MapWithGeometricLevels[K, K](1))
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := b.N; n > 0; n-- {
+			key := gen(rand.Intn(n))
+			kv.Put(key, key)
+		}
+	})
+
 	b.Run("Get", func(b *testing.B) {
 		b.ReportAllocs()
 		b.ResetTimer()
@@ -322,8 +1035,8 @@ func FuzzMapIntPutGet(f *testing.F) {
 			t.Fatalf("pair (%v, %v) should be found", key, val)
 		}
 
-		x, node := kv.Get(el.Key)
-		if node == nil {
+		x, has := kv.Get(el.Key)
+		if !has {
 			t.Errorf("pair (%v, %v) should be found", key, val)
 		}
 
@@ -346,8 +1059,8 @@ func FuzzMapStringPutGet(f *testing.F) {
 			t.Fatalf("pair (%v, %v) should be found", key, val)
 		}
 
-		x, node := kv.Get(el.Key)
-		if node == nil {
+		x, has := kv.Get(el.Key)
+		if !has {
 			t.Errorf("pair (%v, %v) should be found", key, val)
 		}
 
@@ -357,3 +1070,29 @@ func FuzzMapStringPutGet(f *testing.F) {
 	})
 
 }
+
+func TestMapParallelReads(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		kv.Put(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				val, has := kv.Get(i)
+				rank, _ := kv.RankOf(i)
+				it.Then(t).Should(
+					it.True(has),
+					it.Equal(val, i),
+					it.Equal(rank, i),
+					it.Equal(kv.At(i).Key, i),
+				)
+			}
+		}()
+	}
+	wg.Wait()
+}
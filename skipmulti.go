@@ -0,0 +1,115 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// MultiMap is ordered map allowing multiple values per key. Values of
+// the key are kept in the insertion order.
+type MultiMap[K any, V any] struct {
+	kv     *Map[K, []V]
+	length int
+}
+
+// NewMultiMap creates instance of multimap
+func NewMultiMap[K Key, V any]() *MultiMap[K, V] {
+	return NewMultiMapWith[K, V](ord.Type[K]{})
+}
+
+// NewMultiMapWith creates instance of multimap, keys are ordered by
+// the given type class
+func NewMultiMapWith[K any, V any](cmp ord.Ord[K]) *MultiMap[K, V] {
+	return &MultiMap[K, V]{kv: NewMapWith[K, []V](cmp)}
+}
+
+// Length returns number of values
+func (kv *MultiMap[K, V]) Length() int {
+	return kv.length
+}
+
+// Unique returns number of unique keys
+func (kv *MultiMap[K, V]) Unique() int {
+	return kv.kv.Length()
+}
+
+// Put appends value to the key, returns true if key is new
+func (kv *MultiMap[K, V]) Put(key K, val V) bool {
+	isNew, el := kv.kv.getOrInsert(key, nil)
+	el.Value = append(el.Value, val)
+	kv.length++
+
+	return isNew
+}
+
+// Get returns the first value of the key
+func (kv *MultiMap[K, V]) Get(key K) (V, bool) {
+	seq, has := kv.kv.Get(key)
+	if !has {
+		return *new(V), false
+	}
+
+	return seq[0], true
+}
+
+// GetAll returns copy of values of the key in the insertion order
+func (kv *MultiMap[K, V]) GetAll(key K) []V {
+	seq, _ := kv.kv.Get(key)
+	return slices.Clone(seq)
+}
+
+// CutOne removes the first value of the key
+func (kv *MultiMap[K, V]) CutOne(key K) (V, bool) {
+	el := kv.kv.Find(key)
+	if el == nil {
+		return *new(V), false
+	}
+
+	seq := el.Value
+	val := seq[0]
+	if len(seq) == 1 {
+		kv.kv.Cut(key)
+	} else {
+		clear(seq[:1])
+		el.Value = seq[1:]
+	}
+	kv.length--
+
+	return val, true
+}
+
+// CutAll removes all values of the key, returns them in the insertion order
+func (kv *MultiMap[K, V]) CutAll(key K) []V {
+	seq, has := kv.kv.Cut(key)
+	if !has {
+		return nil
+	}
+
+	kv.length -= len(seq)
+	return seq
+}
+
+// All pairs in key order, values of the same key are in the insertion order
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := kv.kv.Values(); e != nil; e = e.Next() {
+			for _, val := range e.Value {
+				if !yield(e.Key, val) {
+					return
+				}
+			}
+		}
+	}
+}
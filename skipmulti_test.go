@@ -0,0 +1,83 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestMultiMap(t *testing.T) {
+	kv := skiplist.NewMultiMap[int, string]()
+
+	it.Then(t).Should(
+		it.Equal(kv.Put(2, "b1"), true),
+		it.Equal(kv.Put(1, "a1"), true),
+		it.Equal(kv.Put(2, "b2"), false),
+		it.Equal(kv.Put(2, "b3"), false),
+		it.Equal(kv.Length(), 4),
+		it.Equal(kv.Unique(), 2),
+		it.Seq(kv.GetAll(2)).Equal("b1", "b2", "b3"),
+	)
+
+	keys, vals := []int{}, []string{}
+	for k, v := range kv.All() {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+	it.Then(t).Should(
+		it.Seq(keys).Equal(1, 2, 2, 2),
+		it.Seq(vals).Equal("a1", "b1", "b2", "b3"),
+	)
+
+	val, has := kv.CutOne(2)
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, "b1"),
+		it.Equal(kv.Length(), 3),
+	)
+
+	val, has = kv.Get(2)
+	it.Then(t).Should(it.True(has), it.Equal(val, "b2"))
+
+	it.Then(t).Should(
+		it.Seq(kv.CutAll(2)).Equal("b2", "b3"),
+		it.Equal(kv.Length(), 1),
+		it.Equal(kv.Unique(), 1),
+		it.True(kv.CutAll(2) == nil),
+	)
+
+	val, has = kv.CutOne(1)
+	_, had := kv.CutOne(1)
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(val, "a1"),
+		it.True(!had),
+		it.Equal(kv.Length(), 0),
+		it.Equal(kv.Unique(), 0),
+	)
+}
+
+func TestMultiMapGetAllCopy(t *testing.T) {
+	kv := skiplist.NewMultiMap[int, string]()
+	kv.Put(1, "a1")
+	kv.Put(1, "a2")
+
+	seq := kv.GetAll(1)
+	seq[0] = "x"
+	_ = append(seq[:1], "y")
+	kv.Put(1, "a3")
+
+	it.Then(t).Should(
+		it.Seq(kv.GetAll(1)).Equal("a1", "a2", "a3"),
+		it.Seq(seq).Equal("x", "y"),
+	)
+}
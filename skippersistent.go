@@ -0,0 +1,291 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"sort"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// PersistentMap is immutable (functional) skip list, Put and Cut return
+// a new map that shares structure with the original one. Any version of
+// the map is a cheap snapshot, which is safe to read without locks.
+//
+// The skip list is stored top-down: the node of level i holds the nodes of
+// level i-1 that are between two consecutive keys of level i express lane.
+// Writes copy nodes on the search path only, it takes O(log n) expected.
+type PersistentMap[K any, V any] struct {
+	root    *pnode[K, V]
+	height  int
+	length  int
+	compare func(K, K) int
+}
+
+// pair of persistent map, the level is a number of express lanes
+// that the key belongs to above level 0.
+type pentry[K any, V any] struct {
+	key   K
+	value V
+	level int
+}
+
+// node of persistent map, nodes are never modified after construction.
+// Every node except the leftmost one starts with the key, which level is
+// greater or equal to the level of node.
+type pnode[K any, V any] struct {
+	// the first keys of children, the key of leftmost child is unused
+	keys []K
+	kids []*pnode[K, V]
+
+	// pairs of level 0 node
+	pairs []pentry[K, V]
+}
+
+// NewPersistentMap creates empty instance of persistent map
+func NewPersistentMap[K Key, V any]() *PersistentMap[K, V] {
+	return NewPersistentMapWith[K, V](ord.Type[K]{})
+}
+
+// NewPersistentMapWith creates empty instance of persistent map, keys are
+// ordered by the given type class
+func NewPersistentMapWith[K any, V any](cmp ord.Ord[K]) *PersistentMap[K, V] {
+	return &PersistentMap[K, V]{
+		root:    &pnode[K, V]{},
+		compare: cmp.Compare,
+	}
+}
+
+func (kv *PersistentMap[K, V]) Length() int {
+	return kv.length
+}
+
+// Max level of skip list
+func (kv *PersistentMap[K, V]) Level() int {
+	return kv.height
+}
+
+// descend from the root to the node of level 0, path contains nodes
+// of each level and index of child chosen at the level.
+func (kv *PersistentMap[K, V]) descend(key K, path *[L]*pnode[K, V], idx *[L]int) *pnode[K, V] {
+	node := kv.root
+	for lvl := kv.height; lvl > 0; lvl-- {
+		i := sort.Search(len(node.kids)-1, func(i int) bool {
+			return kv.compare(node.keys[i+1], key) > 0
+		})
+
+		path[lvl], idx[lvl] = node, i
+		node = node.kids[i]
+	}
+
+	return node
+}
+
+func (kv *PersistentMap[K, V]) search(node *pnode[K, V], key K) (int, bool) {
+	pos := sort.Search(len(node.pairs), func(i int) bool {
+		return kv.compare(node.pairs[i].key, key) >= 0
+	})
+
+	return pos, pos < len(node.pairs) && kv.compare(node.pairs[pos].key, key) == 0
+}
+
+// copy nodes of the path starting from the level, the node replaces
+// the child of the path at the level
+func (kv *PersistentMap[K, V]) copyPath(path *[L]*pnode[K, V], idx *[L]int, from, height int, node *pnode[K, V]) *pnode[K, V] {
+	for lvl := from; lvl <= height; lvl++ {
+		p := path[lvl]
+		n := &pnode[K, V]{keys: p.keys, kids: slices.Clone(p.kids)}
+		n.kids[idx[lvl]] = node
+		node = n
+	}
+
+	return node
+}
+
+func (kv *PersistentMap[K, V]) level() int {
+	// See: https://golang.org/src/math/rand/rand.go#L150
+	p := float64(rand.Int63()) / (1 << 63)
+
+	level := 0
+	for level < L-1 && p < probabilityTable[level+1] {
+		level++
+	}
+
+	return level
+}
+
+// Get value of the key
+func (kv *PersistentMap[K, V]) Get(key K) (V, bool) {
+	var (
+		path [L]*pnode[K, V]
+		idx  [L]int
+	)
+
+	node := kv.descend(key, &path, &idx)
+	if pos, has := kv.search(node, key); has {
+		return node.pairs[pos].value, true
+	}
+
+	return *new(V), false
+}
+
+// Put key-value pair, returns a new version of the map
+func (kv *PersistentMap[K, V]) Put(key K, val V) *PersistentMap[K, V] {
+	var (
+		path [L]*pnode[K, V]
+		idx  [L]int
+	)
+
+	node := kv.descend(key, &path, &idx)
+	pos, has := kv.search(node, key)
+
+	if has {
+		leaf := &pnode[K, V]{pairs: slices.Clone(node.pairs)}
+		leaf.pairs[pos].value = val
+
+		return &PersistentMap[K, V]{
+			root:    kv.copyPath(&path, &idx, 1, kv.height, leaf),
+			height:  kv.height,
+			length:  kv.length,
+			compare: kv.compare,
+		}
+	}
+
+	level := kv.level()
+
+	root, height := kv.root, kv.height
+	for height < level {
+		root = &pnode[K, V]{keys: make([]K, 1), kids: []*pnode[K, V]{root}}
+		height++
+		path[height], idx[height] = root, 0
+	}
+
+	pairs := slices.Insert(slices.Clone(node.pairs), pos, pentry[K, V]{key: key, value: val, level: level})
+
+	// the key heads new nodes on levels below its level, the node of
+	// each level is split at the key
+	left, right := &pnode[K, V]{pairs: pairs[:pos:pos]}, &pnode[K, V]{pairs: pairs[pos:]}
+	if level == 0 {
+		left, right = &pnode[K, V]{pairs: pairs}, nil
+	}
+
+	for lvl := 1; lvl <= level; lvl++ {
+		p, c := path[lvl], idx[lvl]
+		keys := slices.Insert(slices.Clone(p.keys), c+1, key)
+		kids := slices.Insert(slices.Clone(p.kids), c+1, right)
+		kids[c] = left
+
+		if lvl == level {
+			left, right = &pnode[K, V]{keys: keys, kids: kids}, nil
+			break
+		}
+
+		left = &pnode[K, V]{keys: keys[: c+1 : c+1], kids: kids[: c+1 : c+1]}
+		right = &pnode[K, V]{keys: keys[c+1:], kids: kids[c+1:]}
+	}
+
+	return &PersistentMap[K, V]{
+		root:    kv.copyPath(&path, &idx, level+1, height, left),
+		height:  height,
+		length:  kv.length + 1,
+		compare: kv.compare,
+	}
+}
+
+// Cut key from the map, returns a new version of the map
+func (kv *PersistentMap[K, V]) Cut(key K) *PersistentMap[K, V] {
+	var (
+		path [L]*pnode[K, V]
+		idx  [L]int
+	)
+
+	node := kv.descend(key, &path, &idx)
+	pos, has := kv.search(node, key)
+	if !has {
+		return kv
+	}
+
+	var root *pnode[K, V]
+
+	level := min(node.pairs[pos].level, kv.height)
+	if pos > 0 || level == 0 || idx[level] == 0 {
+		leaf := &pnode[K, V]{pairs: slices.Delete(slices.Clone(node.pairs), pos, pos+1)}
+		root = kv.copyPath(&path, &idx, 1, kv.height, leaf)
+	} else {
+		// the key heads nodes on levels below its level, these nodes
+		// are merged with left siblings
+		p, c := path[level], idx[level]
+		merged := &pnode[K, V]{
+			keys: slices.Delete(slices.Clone(p.keys), c, c+1),
+			kids: slices.Delete(slices.Clone(p.kids), c, c+1),
+		}
+		merged.kids[c-1] = kv.merge(p.kids[c-1], p.kids[c], level-1)
+		root = kv.copyPath(&path, &idx, level+1, kv.height, merged)
+	}
+
+	height := kv.height
+	for height > 0 && len(root.kids) == 1 {
+		root = root.kids[0]
+		height--
+	}
+
+	return &PersistentMap[K, V]{
+		root:    root,
+		height:  height,
+		length:  kv.length - 1,
+		compare: kv.compare,
+	}
+}
+
+// merge nodes of the level, the first key of b is removed
+func (kv *PersistentMap[K, V]) merge(a, b *pnode[K, V], level int) *pnode[K, V] {
+	if level == 0 {
+		return &pnode[K, V]{pairs: slices.Concat(a.pairs, b.pairs[1:])}
+	}
+
+	n := len(a.kids)
+	node := &pnode[K, V]{
+		keys: slices.Concat(a.keys, b.keys[1:]),
+		kids: slices.Concat(a.kids[:n-1], []*pnode[K, V]{nil}, b.kids[1:]),
+	}
+	node.kids[n-1] = kv.merge(a.kids[n-1], b.kids[0], level-1)
+
+	return node
+}
+
+// All pairs of the map in key order
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *PersistentMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		kv.walk(kv.root, kv.height, yield)
+	}
+}
+
+func (kv *PersistentMap[K, V]) walk(node *pnode[K, V], level int, yield func(K, V) bool) bool {
+	if level == 0 {
+		for _, e := range node.pairs {
+			if !yield(e.key, e.value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, kid := range node.kids {
+		if !kv.walk(kid, level-1, yield) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,102 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestPersistentMap(t *testing.T) {
+	type version struct {
+		kv   *skiplist.PersistentMap[int, int]
+		keys map[int]int
+	}
+
+	kv := skiplist.NewPersistentMap[int, int]()
+	ref := map[int]int{}
+	versions := []version{}
+
+	for i := 0; i < 5000; i++ {
+		key := rand.Intn(500)
+		if rand.Intn(3) == 0 {
+			kv = kv.Cut(key)
+			delete(ref, key)
+		} else {
+			kv = kv.Put(key, i)
+			ref[key] = i
+		}
+
+		if i%500 == 0 {
+			keys := map[int]int{}
+			for k, v := range ref {
+				keys[k] = v
+			}
+			versions = append(versions, version{kv: kv, keys: keys})
+		}
+	}
+	versions = append(versions, version{kv: kv, keys: ref})
+
+	for _, ver := range versions {
+		expect := []int{}
+		for k := range ver.keys {
+			expect = append(expect, k)
+		}
+		sort.Ints(expect)
+
+		keys := []int{}
+		for k, v := range ver.kv.All() {
+			it.Then(t).Should(it.Equal(v, ver.keys[k]))
+			keys = append(keys, k)
+		}
+
+		it.Then(t).Should(
+			it.Equal(ver.kv.Length(), len(ver.keys)),
+			it.Seq(keys).Equal(expect...),
+		)
+
+		for k := 0; k < 500; k++ {
+			val, has := ver.kv.Get(k)
+			exp, ok := ver.keys[k]
+			it.Then(t).Should(
+				it.Equal(has, ok),
+				it.Equal(val, exp),
+			)
+		}
+	}
+}
+
+func TestPersistentMapCut(t *testing.T) {
+	kv := skiplist.NewPersistentMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		kv = kv.Put(i, i)
+	}
+
+	empty := kv
+	for i := 0; i < 1000; i++ {
+		empty = empty.Cut(i)
+	}
+
+	n := 0
+	for range empty.All() {
+		n++
+	}
+
+	it.Then(t).Should(
+		it.Equal(kv.Length(), 1000),
+		it.Equal(empty.Length(), 0),
+		it.Equal(empty.Level(), 0),
+		it.Equal(n, 0),
+		it.True(kv.Cut(-1) == kv),
+	)
+}
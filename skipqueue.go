@@ -0,0 +1,135 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"github.com/fogfish/skiplist/ord"
+)
+
+// Queue is double-ended priority queue of values. Values are unique and
+// identify queued items, priorities are ordered by K. Values of the same
+// priority are dequeued in the insertion order. Push, Pop, Peek and
+// UpdatePriority takes O(log n).
+type Queue[K any, V comparable] struct {
+	kv    *Map[qkey[K], V]
+	index map[V]qkey[K]
+	seq   uint64
+}
+
+// priority of value, the sequence number keeps insertion order
+type qkey[K any] struct {
+	priority K
+	seq      uint64
+}
+
+// NewQueue creates instance of priority queue
+func NewQueue[K Key, V comparable]() *Queue[K, V] {
+	return NewQueueWith[K, V](ord.Type[K]{})
+}
+
+// NewQueueWith creates instance of priority queue, priorities are ordered
+// by the given type class
+func NewQueueWith[K any, V comparable](cmp ord.Ord[K]) *Queue[K, V] {
+	compare := func(a, b qkey[K]) int {
+		if c := cmp.Compare(a.priority, b.priority); c != 0 {
+			return c
+		}
+
+		switch {
+		case a.seq < b.seq:
+			return -1
+		case a.seq > b.seq:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return &Queue[K, V]{
+		kv:    NewMapFunc[qkey[K], V](compare),
+		index: make(map[V]qkey[K]),
+	}
+}
+
+// Length returns number of queued values
+func (q *Queue[K, V]) Length() int {
+	return q.kv.Length()
+}
+
+// Push value with the priority, the priority is updated if value is queued.
+// Returns true if value is new.
+func (q *Queue[K, V]) Push(priority K, val V) bool {
+	key, has := q.index[val]
+	if has {
+		q.kv.Cut(key)
+	}
+
+	q.seq++
+	key = qkey[K]{priority: priority, seq: q.seq}
+	q.kv.Put(key, val)
+	q.index[val] = key
+
+	return !has
+}
+
+// UpdatePriority of queued value, returns false if value is not queued
+func (q *Queue[K, V]) UpdatePriority(val V, priority K) bool {
+	if _, has := q.index[val]; !has {
+		return false
+	}
+
+	q.Push(priority, val)
+	return true
+}
+
+// Priority of queued value
+func (q *Queue[K, V]) Priority(val V) (K, bool) {
+	key, has := q.index[val]
+	return key.priority, has
+}
+
+// Peek value with the lowest priority
+func (q *Queue[K, V]) Peek() (K, V, bool) {
+	return q.peek(q.kv.Values())
+}
+
+// PeekMax value with the highest priority
+func (q *Queue[K, V]) PeekMax() (K, V, bool) {
+	return q.peek(q.kv.At(q.kv.Length() - 1))
+}
+
+func (q *Queue[K, V]) peek(el *Pair[qkey[K], V]) (K, V, bool) {
+	if el == nil {
+		return *new(K), *new(V), false
+	}
+
+	return el.Key.priority, el.Value, true
+}
+
+// Pop value with the lowest priority
+func (q *Queue[K, V]) Pop() (K, V, bool) {
+	return q.pop(q.kv.Values())
+}
+
+// PopMax value with the highest priority
+func (q *Queue[K, V]) PopMax() (K, V, bool) {
+	return q.pop(q.kv.At(q.kv.Length() - 1))
+}
+
+func (q *Queue[K, V]) pop(el *Pair[qkey[K], V]) (K, V, bool) {
+	if el == nil {
+		return *new(K), *new(V), false
+	}
+
+	key, val := el.Key, el.Value
+	q.kv.Cut(key)
+	delete(q.index, val)
+
+	return key.priority, val, true
+}
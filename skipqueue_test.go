@@ -0,0 +1,75 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestQueue(t *testing.T) {
+	q := skiplist.NewQueue[int, string]()
+
+	it.Then(t).Should(
+		it.Equal(q.Push(5, "e"), true),
+		it.Equal(q.Push(1, "a"), true),
+		it.Equal(q.Push(3, "c"), true),
+		it.Equal(q.Push(3, "d"), true),
+		it.Equal(q.Push(9, "z"), true),
+		it.Equal(q.Length(), 5),
+	)
+
+	prio, val, has := q.Peek()
+	it.Then(t).Should(it.True(has), it.Equal(prio, 1), it.Equal(val, "a"))
+
+	prio, val, has = q.PeekMax()
+	it.Then(t).Should(it.True(has), it.Equal(prio, 9), it.Equal(val, "z"))
+
+	it.Then(t).Should(
+		it.Equal(q.UpdatePriority("e", 0), true),
+		it.Equal(q.UpdatePriority("x", 0), false),
+		it.Equal(q.Push(10, "a"), false),
+		it.Equal(q.Length(), 5),
+	)
+
+	prio, has = q.Priority("a")
+	it.Then(t).Should(it.True(has), it.Equal(prio, 10))
+
+	seq := []string{}
+	for {
+		_, val, has := q.Pop()
+		if !has {
+			break
+		}
+		seq = append(seq, val)
+	}
+	it.Then(t).Should(
+		it.Seq(seq).Equal("e", "c", "d", "z", "a"),
+		it.Equal(q.Length(), 0),
+	)
+
+	_, _, has = q.PopMax()
+	it.Then(t).Should(it.True(!has))
+}
+
+func TestQueuePopMax(t *testing.T) {
+	q := skiplist.NewQueue[int, int]()
+	for i := 0; i < 100; i++ {
+		q.Push(i%10, i)
+	}
+
+	prev := 10
+	for q.Length() > 0 {
+		prio, _, _ := q.PopMax()
+		it.Then(t).Should(it.LessOrEqual(prio, prev))
+		prev = prio
+	}
+}
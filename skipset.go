@@ -14,6 +14,8 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+
+	"github.com/fogfish/skiplist/ord"
 )
 
 // Each element is represented by a Element in a skip structures. Each node has
@@ -22,9 +24,13 @@ import (
 // a node with a random level is inserted to represent the element. Random levels
 // are generated with a simple pattern: 50% are level 1, 25% are level 2, 12.5% are
 // level 3 and so on.
-type Element[K Key] struct {
+type Element[K any] struct {
 	Key     K
 	Fingers []*Element[K]
+
+	// number of elements skipped by each finger, the span of finger
+	// at level i is a distance (in level 0 hops) to the element Fingers[i].
+	spans []int
 }
 
 // Rank of node
@@ -65,7 +71,7 @@ func (el *Element[K]) String() string {
 // --------------------------------------------------------------------------------------
 
 // Set of Elements
-type Set[K Key] struct {
+type Set[K any] struct {
 	//
 	// head of the list, the node is a lowest element
 	head *Element[K]
@@ -81,30 +87,51 @@ type Set[K Key] struct {
 	// random generator
 	random rand.Source
 
-	//
-	// buffer to estimate the skip path during insert / remove
-	// the buffer implements optimization of memory allocations
-	path [L]*Element[K]
-
 	//
 	ptable [L]float64
 
 	// memory allocator for elements
 	malloc Allocator[K, Element[K]]
+
+	// total ordering of keys
+	compare func(K, K) int
+
+	// keys are ordered in reverse of the type class
+	desc bool
+
+	// hash of keys, levels are derived from the hash instead of random
+	// generator if it is defined
+	hash func(K) uint64
+
+	// fast generator of geometric levels, it replaces random generator
+	// and probability table if it is defined
+	geometric *geometric
 }
 
 // New create instance of SkipList
 func NewSet[K Key](opts ...SetConfig[K]) *Set[K] {
-	head := &Element[K]{Fingers: make([]*Element[K], L)}
+	return NewSetFunc[K](ord.Type[K]{}.Compare, opts...)
+}
+
+// NewSetWith create instance of SkipList, keys are ordered by the given type class
+func NewSetWith[K any](cmp ord.Ord[K], opts ...SetConfig[K]) *Set[K] {
+	return NewSetFunc[K](cmp.Compare, opts...)
+}
+
+// NewSetFunc create instance of SkipList, keys are ordered by the compare
+// function. The function is called directly, it avoids dynamic dispatch of
+// the type class.
+func NewSetFunc[K any](compare func(a, b K) int, opts ...SetConfig[K]) *Set[K] {
+	head := &Element[K]{Fingers: make([]*Element[K], L), spans: make([]int, L)}
 
 	set := &Set[K]{
-		head:   head,
-		null:   *new(K),
-		length: 0,
-		random: rand.NewSource(time.Now().UnixNano()),
-		path:   [L]*Element[K]{},
-		ptable: probabilityTable,
-		malloc: nil,
+		head:    head,
+		null:    *new(K),
+		length:  0,
+		random:  rand.NewSource(time.Now().UnixNano()),
+		ptable:  probabilityTable,
+		malloc:  nil,
+		compare: compare,
 	}
 
 	for _, opt := range opts {
@@ -114,6 +141,40 @@ func NewSet[K Key](opts ...SetConfig[K]) *Set[K] {
 	return set
 }
 
+// NewSetFromSorted builds instance of SkipList from sorted keys in O(n).
+// Levels are assigned deterministically, each level contains every 1/p-th
+// element of the level below. Keys must be unique and sorted in ascending order.
+func NewSetFromSorted[K Key](keys []K, opts ...SetConfig[K]) *Set[K] {
+	set := NewSet(opts...)
+	levels := newSortedLevels(&set.ptable)
+
+	tail := [L]*Element[K]{}
+	for i := range tail {
+		tail[i] = set.head
+	}
+
+	for i, key := range keys {
+		if i > 0 && set.compare(keys[i-1], key) >= 0 {
+			panic("keys are not sorted")
+		}
+
+		pos := i + 1
+		lvl := levels.level(pos)
+
+		el := set.NewElement(key, lvl)
+		el.Key = key
+
+		for level := 0; level < lvl; level++ {
+			tail[level].Fingers[level] = el
+			tail[level].spans[level] = levels.span(level, pos)
+			tail[level] = el
+		}
+	}
+
+	set.length = len(keys)
+	return set
+}
+
 // Cast set into string
 func (set *Set[K]) String() string {
 	sb := strings.Builder{}
@@ -148,12 +209,12 @@ func (set *Set[K]) Level() int {
 // of level i or higher that is to the left of the location of the
 // insertion/deletion.
 func (set *Set[K]) Skip(level int, key K) (*Element[K], [L]*Element[K]) {
-	path := set.path
+	var path [L]*Element[K]
 
 	node := set.head
 	next := node.Fingers
 	for lev := L - 1; lev >= level; lev-- {
-		for next[lev] != nil && next[lev].Key < key {
+		for next[lev] != nil && set.compare(next[lev].Key, key) < 0 {
 			node = node.Fingers[lev]
 			next = node.Fingers
 		}
@@ -163,34 +224,133 @@ func (set *Set[K]) Skip(level int, key K) (*Element[K], [L]*Element[K]) {
 	return next[level], path
 }
 
+// skipWithRank is skip algorithm that also estimates position (rank) of each
+// node on the path. The position of head is 0, the first element is 1.
+func (set *Set[K]) skipWithRank(key K) (*Element[K], [L]*Element[K], [L]int) {
+	var path [L]*Element[K]
+	rank := [L]int{}
+
+	node := set.head
+	next := node.Fingers
+	pos := 0
+	for lev := L - 1; lev >= 0; lev-- {
+		for next[lev] != nil && set.compare(next[lev].Key, key) < 0 {
+			pos += node.spans[lev]
+			node = node.Fingers[lev]
+			next = node.Fingers
+		}
+		path[lev] = node
+		rank[lev] = pos
+	}
+
+	return next[0], path, rank
+}
+
 // Add element to set, return true if element is new
-func (set *Set[K]) Add(key K) (bool, *Element[K]) {
-	el, path := set.Skip(0, key)
+func (set *Set[K]) Add(key K) bool {
+	el, path, rank := set.skipWithRank(key)
+
+	if el != nil && set.compare(el.Key, key) == 0 {
+		return false
+	}
+
+	set.insert(path, rank, key)
+	return true
+}
+
+// AddAll adds elements to the set, returns number of new elements.
+// Sorted runs of keys are spliced starting from the last insertion point.
+func (set *Set[K]) AddAll(keys ...K) int {
+	var (
+		el   *Element[K]
+		path [L]*Element[K]
+		rank [L]int
+	)
+
+	n := 0
+	for i, key := range keys {
+		if i > 0 && set.compare(keys[i-1], key) < 0 {
+			el = set.skipFrom(key, &path, &rank)
+		} else {
+			el, path, rank = set.skipWithRank(key)
+		}
+
+		if el != nil && set.compare(el.Key, key) == 0 {
+			continue
+		}
+
+		pos := rank[0] + 1
+		el = set.insert(path, rank, key)
+		for level := 0; level < L && path[level].Fingers[level] == el; level++ {
+			path[level], rank[level] = el, pos
+		}
+		n++
+	}
+
+	return n
+}
+
+// skipFrom continues skip algorithm from the given path, the path shall
+// points to nodes that are less than key.
+func (set *Set[K]) skipFrom(key K, path *[L]*Element[K], rank *[L]int) *Element[K] {
+	top := 0
+	for top < L && path[top].Fingers[top] != nil && set.compare(path[top].Fingers[top].Key, key) < 0 {
+		top++
+	}
+
+	node, pos := set.head, 0
+	for lev := top - 1; lev >= 0; lev-- {
+		if rank[lev] > pos {
+			node, pos = path[lev], rank[lev]
+		}
 
-	if el != nil && el.Key == key {
-		return false, el
+		for node.Fingers[lev] != nil && set.compare(node.Fingers[lev].Key, key) < 0 {
+			pos += node.spans[lev]
+			node = node.Fingers[lev]
+		}
+		path[lev], rank[lev] = node, pos
 	}
 
-	rank, el := set.CreateElement(L, key)
+	return path[0].Fingers[0]
+}
+
+// insert new element after the path
+func (set *Set[K]) insert(path [L]*Element[K], rank [L]int, key K) *Element[K] {
+	lvl, el := set.CreateElement(L, key)
 
 	// re-bind fingers to new node
-	for level := 0; level < rank; level++ {
+	pos := rank[0] + 1
+	for level := 0; level < lvl; level++ {
 		el.Fingers[level] = path[level].Fingers[level]
+		el.spans[level] = rank[level] + path[level].spans[level] + 1 - pos
 		path[level].Fingers[level] = el
+		path[level].spans[level] = pos - rank[level]
+	}
+
+	// fingers above the node skips one more element
+	for level := lvl; level < L; level++ {
+		path[level].spans[level]++
 	}
 
 	set.length++
-	return true, el
+	return el
 }
 
 // mkNode creates a new node, randomly defines empty fingers (level of the node)
 func (set *Set[K]) CreateElement(maxL int, key K) (int, *Element[K]) {
-	// See: https://golang.org/src/math/rand/rand.go#L150
-	p := float64(set.random.Int63()) / (1 << 63)
-
 	level := 0
-	for level < maxL && p < set.ptable[level] {
-		level++
+	if set.geometric != nil && set.hash == nil {
+		level = set.geometric.level(maxL)
+	} else {
+		// See: https://golang.org/src/math/rand/rand.go#L150
+		p := float64(set.random.Int63()) / (1 << 63)
+		if set.hash != nil {
+			p = float64(set.hash(key)>>1) / (1 << 63)
+		}
+
+		for level < maxL && p < set.ptable[level] {
+			level++
+		}
 	}
 
 	node := set.NewElement(key, level)
@@ -201,50 +361,137 @@ func (set *Set[K]) CreateElement(maxL int, key K) (int, *Element[K]) {
 
 // allocate new node
 func (set *Set[K]) NewElement(key K, rank int) *Element[K] {
+	if malloc, ok := set.malloc.(NodeAllocator[K, Element[K]]); ok {
+		el := malloc.AllocNode(key, rank)
+		if len(el.spans) < rank {
+			el.spans = make([]int, rank)
+		}
+		return el
+	}
+
 	if set.malloc != nil {
-		return set.malloc.Alloc(key)
+		el := set.malloc.Alloc(key)
+		if len(el.spans) < rank {
+			el.spans = make([]int, rank)
+		}
+		return el
 	}
 
-	return &Element[K]{Fingers: make([]*Element[K], rank)}
+	return &Element[K]{
+		Fingers: make([]*Element[K], rank),
+		spans:   make([]int, rank),
+	}
+}
+
+// release node to allocator
+func (set *Set[K]) free(el *Element[K]) {
+	if malloc, ok := set.malloc.(NodeAllocator[K, Element[K]]); ok {
+		malloc.FreeNode(el)
+		return
+	}
+
+	set.malloc.Free(el.Key)
 }
 
 // Check is element exists in set
-func (set *Set[K]) Has(key K) (bool, *Element[K]) {
+func (set *Set[K]) Has(key K) bool {
+	return set.Find(key) != nil
+}
+
+// Find element of the key, returns nil if key does not exist
+func (set *Set[K]) Find(key K) *Element[K] {
 	el, _ := set.Skip(0, key)
 
-	if el != nil && el.Key == key {
-		return true, el
+	if el != nil && set.compare(el.Key, key) == 0 {
+		return el
 	}
 
-	return false, nil
+	return nil
 }
 
 // Cut element from the set, returns true if element is removed
-func (set *Set[K]) Cut(key K) (bool, *Element[K]) {
+func (set *Set[K]) Cut(key K) bool {
 	rank := L
 	v, path := set.Skip(0, key)
 
-	if v == nil || v.Key != key {
-		return false, nil
+	if v == nil || set.compare(v.Key, key) != 0 {
+		return false
 	}
 
 	for level := 0; level < rank; level++ {
 		if path[level].Fingers[level] == v {
 			if len(v.Fingers) > level {
 				path[level].Fingers[level] = v.Fingers[level]
+				path[level].spans[level] += v.spans[level] - 1
 			} else {
 				path[level].Fingers[level] = nil
 			}
+		} else {
+			path[level].spans[level]--
 		}
 	}
 
 	set.length--
 
 	if set.malloc != nil {
-		set.malloc.Free(key)
+		set.free(v)
 	}
 
-	return true, v
+	return true
+}
+
+// CutRange removes all keys in the interval [from, to),
+// returns number of removed keys
+func (set *Set[K]) CutRange(from, to K) int {
+	if set.compare(from, to) >= 0 {
+		return 0
+	}
+
+	_, lo, lrank := set.skipWithRank(from)
+	_, hi, hrank := set.skipWithRank(to)
+
+	n := hrank[0] - lrank[0]
+	if n == 0 {
+		return 0
+	}
+
+	// path to upper bound passes removed elements, they are released
+	// after the path is relinked
+	v := lo[0].Fingers[0]
+	for level := 0; level < L; level++ {
+		lo[level].spans[level] = hrank[level] + hi[level].spans[level] - lrank[level] - n
+		lo[level].Fingers[level] = hi[level].Fingers[level]
+	}
+
+	if set.malloc != nil {
+		for i := 0; i < n; i++ {
+			next := v.Fingers[0]
+			set.free(v)
+			v = next
+		}
+	}
+
+	set.length -= n
+	return n
+}
+
+// Clear removes all elements from the set, the configuration is retained.
+// Removed elements are returned to the allocator.
+func (set *Set[K]) Clear() {
+	if set.malloc != nil {
+		for e := set.head.Fingers[0]; e != nil; {
+			next := e.Fingers[0]
+			set.free(e)
+			e = next
+		}
+	}
+
+	for level := 0; level < L; level++ {
+		set.head.Fingers[level] = nil
+		set.head.spans[level] = 0
+	}
+
+	set.length = 0
 }
 
 // Head of skiplist
@@ -257,70 +504,331 @@ func (set *Set[K]) Values() *Element[K] {
 	return set.head.Fingers[0]
 }
 
+// Set elements on the level, the express lane of skip list
+//
+//	for e := set.ValuesOn(level); e != nil; e = e.NextOn(level) { /* ... */}
+func (set *Set[K]) ValuesOn(level int) *Element[K] {
+	if level < 0 || level >= L {
+		return nil
+	}
+
+	return set.head.Fingers[level]
+}
+
 // Successor elements of key
 func (set *Set[K]) Successor(key K) *Element[K] {
 	el, _ := set.Skip(0, key)
 	return el
 }
 
+// At returns element at position i, elements are indexed from 0
+func (set *Set[K]) At(i int) *Element[K] {
+	if i < 0 || i >= set.length {
+		return nil
+	}
+
+	pos := -1
+	node := set.head
+	for lev := L - 1; lev >= 0; lev-- {
+		for node.Fingers[lev] != nil && pos+node.spans[lev] <= i {
+			pos += node.spans[lev]
+			node = node.Fingers[lev]
+		}
+
+		if pos == i {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// RankOf returns position of the key in the set, elements are indexed from 0.
+// If key is not found, it returns the position where key would be inserted.
+func (set *Set[K]) RankOf(key K) (int, bool) {
+	el, _, rank := set.skipWithRank(key)
+
+	if el != nil && set.compare(el.Key, key) == 0 {
+		return rank[0], true
+	}
+
+	return rank[0], false
+}
+
+// CountRange returns number of keys in the interval [from, to)
+func (set *Set[K]) CountRange(from, to K) int {
+	if set.compare(from, to) >= 0 {
+		return 0
+	}
+
+	_, _, lo := set.skipWithRank(from)
+	_, _, hi := set.skipWithRank(to)
+
+	return hi[0] - lo[0]
+}
+
+// IsSubset returns true if all elements of the set belongs to other set
+func (set *Set[K]) IsSubset(other *Set[K]) bool {
+	if set.length > other.length {
+		return false
+	}
+
+	b := other.head.Fingers[0]
+	for a := set.head.Fingers[0]; a != nil; a = a.Fingers[0] {
+		for b != nil && set.compare(b.Key, a.Key) < 0 {
+			b = b.Fingers[0]
+		}
+
+		if b == nil || set.compare(b.Key, a.Key) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsSuperset returns true if the set contains all elements of other set
+func (set *Set[K]) IsSuperset(other *Set[K]) bool {
+	return other.IsSubset(set)
+}
+
+// Disjoint returns true if sets have no elements in common
+func (set *Set[K]) Disjoint(other *Set[K]) bool {
+	a, b := set.head.Fingers[0], other.head.Fingers[0]
+	for a != nil && b != nil {
+		switch {
+		case set.compare(a.Key, b.Key) < 0:
+			a = a.Fingers[0]
+		case set.compare(b.Key, a.Key) < 0:
+			b = b.Fingers[0]
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equal returns true if sets contain same elements
+func (set *Set[K]) Equal(other *Set[K]) bool {
+	if set.length != other.length {
+		return false
+	}
+
+	a, b := set.head.Fingers[0], other.head.Fingers[0]
+	for a != nil && b != nil {
+		if set.compare(a.Key, b.Key) != 0 {
+			return false
+		}
+		a, b = a.Fingers[0], b.Fingers[0]
+	}
+
+	return a == nil && b == nil
+}
+
+// Seek jumps forward from the element to the first element that is greater
+// or equal to key. The jump uses fingers, it takes O(log d) where d is
+// a distance between elements.
+func (set *Set[K]) Seek(el *Element[K], key K) *Element[K] {
+	if el == nil || set.compare(el.Key, key) >= 0 {
+		return el
+	}
+
+	node, lev := el, 0
+	for {
+		for lev+1 < len(node.Fingers) && node.Fingers[lev+1] != nil && set.compare(node.Fingers[lev+1].Key, key) < 0 {
+			lev++
+		}
+
+		if next := node.Fingers[lev]; next != nil && set.compare(next.Key, key) < 0 {
+			node = next
+			continue
+		}
+
+		if lev == 0 {
+			return node.Fingers[0]
+		}
+		lev--
+	}
+}
+
 // Split set of elements by key
 func (set *Set[K]) Split(key K) *Set[K] {
-	node, path := set.Skip(0, key)
+	_, path, rank := set.skipWithRank(key)
 
+	head := &Element[K]{Fingers: make([]*Element[K], L), spans: make([]int, L)}
 	for level, x := range path {
+		head.Fingers[level] = x.Fingers[level]
+		head.spans[level] = rank[level] + x.spans[level] - rank[0]
 		x.Fingers[level] = nil
+		x.spans[level] = 0
 	}
 
-	head := &Element[K]{Fingers: make([]*Element[K], L)}
-
 	tail := &Set[K]{
-		head:   head,
-		null:   *new(K),
-		length: 0,
-		random: set.random,
-		path:   [L]*Element[K]{},
-		ptable: set.ptable,
-		malloc: set.malloc,
-	}
-	tail.head.Fingers[0] = node
-
-	length := 0
-	for n := node; n != nil; n = n.Fingers[0] {
-		length++
+		head:      head,
+		null:      *new(K),
+		length:    0,
+		random:    set.random,
+		ptable:    set.ptable,
+		malloc:    set.malloc,
+		compare:   set.compare,
+		desc:      set.desc,
+		hash:      set.hash,
+		geometric: set.geometric,
 	}
 
+	length := set.length - rank[0]
 	tail.length = length
 	set.length -= length
 
 	return tail
 }
 
+// Clone makes a copy of the set, the copy has identical structure
+func (set *Set[K]) Clone() *Set[K] {
+	head := &Element[K]{Fingers: make([]*Element[K], L), spans: make([]int, L)}
+	copy(head.spans, set.head.spans)
+
+	clone := &Set[K]{
+		head:      head,
+		null:      *new(K),
+		length:    set.length,
+		random:    set.random,
+		ptable:    set.ptable,
+		malloc:    set.malloc,
+		compare:   set.compare,
+		desc:      set.desc,
+		hash:      set.hash,
+		geometric: set.geometric,
+	}
+
+	tail := [L]*Element[K]{}
+	for i := range tail {
+		tail[i] = head
+	}
+
+	for e := set.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		el := clone.NewElement(e.Key, len(e.Fingers))
+		el.Key = e.Key
+		copy(el.spans, e.spans)
+
+		for level := range e.Fingers {
+			tail[level].Fingers[level] = el
+			tail[level] = el
+		}
+	}
+
+	return clone
+}
+
+// Join is inverse of Split, it appends the tail to the set.
+// Keys of the tail must be greater than keys of the set, otherwise
+// false is returned and both sets remain unchanged. The tail is empty
+// after the operation.
+func (set *Set[K]) Join(tail *Set[K]) bool {
+	var (
+		path [L]*Element[K]
+		rank [L]int
+	)
+
+	node, pos := set.head, 0
+	for lev := L - 1; lev >= 0; lev-- {
+		for node.Fingers[lev] != nil {
+			pos += node.spans[lev]
+			node = node.Fingers[lev]
+		}
+		path[lev], rank[lev] = node, pos
+	}
+
+	first := tail.head.Fingers[0]
+	if first == nil {
+		return true
+	}
+
+	if path[0] != set.head && set.compare(path[0].Key, first.Key) >= 0 {
+		return false
+	}
+
+	for lev := 0; lev < L; lev++ {
+		if tail.head.Fingers[lev] != nil {
+			path[lev].Fingers[lev] = tail.head.Fingers[lev]
+			path[lev].spans[lev] = set.length - rank[lev] + tail.head.spans[lev]
+		}
+		tail.head.Fingers[lev] = nil
+		tail.head.spans[lev] = 0
+	}
+
+	set.length += tail.length
+	tail.length = 0
+
+	return true
+}
+
+// reverse ordering of keys
+func (set *Set[K]) reverse() {
+	compare := set.compare
+	set.compare = func(a, b K) int { return compare(b, a) }
+	set.desc = !set.desc
+}
+
 // --------------------------------------------------------------------------------------
 
 // Configure Set properties
-type SetConfig[K Key] func(*Set[K])
+type SetConfig[K any] func(*Set[K])
 
 // Configure Random Generator
-func SetWithRandomSource[K Key](random rand.Source) SetConfig[K] {
+func SetWithRandomSource[K any](random rand.Source) SetConfig[K] {
 	return func(set *Set[K]) {
 		set.random = random
 	}
 }
 
+// Configure deterministic levels of nodes, the level is derived from hash
+// of the key. The structure depends on the keys only, identical keys produce
+// identical structure regardless of the order of insertion.
+func SetWithDeterministicLevels[K Key]() SetConfig[K] {
+	return SetWithHash[K](hashOf[K])
+}
+
+// Configure hash of keys, levels of nodes are derived from the hash
+// (see SetWithDeterministicLevels)
+func SetWithHash[K any](hash func(K) uint64) SetConfig[K] {
+	return func(set *Set[K]) {
+		set.hash = hash
+	}
+}
+
+// Configure fast generator of levels, the level is drawn from trailing zero
+// bits of xorshift generator, each level takes 1/2ⁿ of nodes below (p = 2⁻ⁿ).
+// It avoids float arithmetic and the scan of probability table.
+func SetWithGeometricLevels[K any](n int) SetConfig[K] {
+	return func(set *Set[K]) {
+		SetWithProbability[K](math.Pow(2, -float64(n)))(set)
+		set.geometric = newGeometric(n, uint64(time.Now().UnixNano()))
+	}
+}
+
 // Configure Memory Allocator
-func SetWithAllocator[K Key](malloc Allocator[K, Element[K]]) SetConfig[K] {
+func SetWithAllocator[K any](malloc Allocator[K, Element[K]]) SetConfig[K] {
 	return func(set *Set[K]) {
 		set.malloc = malloc
 	}
 }
 
+// Configure descending ordering of keys
+func SetWithDescending[K any]() SetConfig[K] {
+	return func(set *Set[K]) {
+		set.reverse()
+	}
+}
+
 // Configure Probability table
 // Use math.Log(B)/B < p < math.Pow(B, -0.5)
 //
 // The probability help to control the "distance" between elements on each level
 // Use p = math.Pow(B, -0.5), where B is number of elements
 // On L1 distance is √B, L2 distance is B, Ln distance is (√B)ⁿ
-func SetWithProbability[K Key](p float64) SetConfig[K] {
+func SetWithProbability[K any](p float64) SetConfig[K] {
 	return func(set *Set[K]) {
 		var ptable [L]float64
 
@@ -333,6 +841,6 @@ func SetWithProbability[K Key](p float64) SetConfig[K] {
 }
 
 // Configure Probability table so that each level takes (√B)ⁿ elements
-func SetWithBlockSize[K Key](b int) SetConfig[K] {
+func SetWithBlockSize[K any](b int) SetConfig[K] {
 	return SetWithProbability[K](math.Pow(float64(b), -0.5))
 }
@@ -12,11 +12,14 @@ import (
 	"math/rand"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/fogfish/it/v2"
 	"github.com/fogfish/skiplist"
+	"github.com/fogfish/skiplist/ord"
 )
 
 // ---------------------------------------------------------------
@@ -36,13 +39,11 @@ func SetSuite[K skiplist.Key](t *testing.T, seq []K) {
 	)
 
 	t.Run("Add", func(t *testing.T) {
-		f := func(has bool, node *skiplist.Element[K]) bool { return has }
-
 		for _, el := range seq {
 			it.Then(t).Should(
-				it.True(f(set.Add(el))),
+				it.True(set.Add(el)),
 			).ShouldNot(
-				it.True(f(set.Add(el))),
+				it.True(set.Add(el)),
 			)
 		}
 
@@ -52,12 +53,30 @@ func SetSuite[K skiplist.Key](t *testing.T, seq []K) {
 		)
 	})
 
-	t.Run("Has", func(t *testing.T) {
-		f := func(has bool, node *skiplist.Element[K]) bool { return has }
+	t.Run("AddAll", func(t *testing.T) {
+		other := skiplist.NewSet[K]()
+
+		it.Then(t).Should(
+			it.Equal(other.AddAll(seq...), len(seq)),
+			it.Equal(other.AddAll(sorted...), 0),
+			it.Equal(other.Length(), len(seq)),
+		)
 
+		values := other.Values()
+		for i := 0; i < len(sorted); i++ {
+			it.Then(t).Should(
+				it.Equal(values.Key, sorted[i]),
+				it.Equal(other.At(i).Key, sorted[i]),
+			)
+			values = values.Next()
+		}
+	})
+
+	t.Run("Has", func(t *testing.T) {
 		for _, el := range seq {
 			it.Then(t).Should(
-				it.True(f(set.Has(el))),
+				it.True(set.Has(el)),
+				it.Equal(set.Find(el).Key, el),
 			)
 		}
 	})
@@ -106,20 +125,111 @@ func SetSuite[K skiplist.Key](t *testing.T, seq []K) {
 		)
 	})
 
-	t.Run("Cut", func(t *testing.T) {
-		f := func(has bool, node *skiplist.Element[K]) bool { return has }
+	t.Run("At", func(t *testing.T) {
+		for i := 0; i < len(sorted); i++ {
+			it.Then(t).Should(
+				it.Equal(set.At(i).Key, sorted[i]),
+			)
+		}
+
+		it.Then(t).Should(
+			it.True(set.At(-1) == nil),
+			it.True(set.At(len(sorted)) == nil),
+		)
+	})
+
+	t.Run("RankOf", func(t *testing.T) {
+		for i := 0; i < len(sorted); i++ {
+			rank, has := set.RankOf(sorted[i])
+			it.Then(t).Should(
+				it.True(has),
+				it.Equal(rank, i),
+			)
+		}
+	})
+
+	t.Run("CountRange", func(t *testing.T) {
+		for _, k := range [][]int{
+			{0, 0},
+			{0, len(sorted) / 4},
+			{len(sorted) / 4, len(sorted) / 2},
+			{len(sorted) / 2, len(sorted) - 1},
+			{0, len(sorted) - 1},
+		} {
+			it.Then(t).Should(
+				it.Equal(set.CountRange(sorted[k[0]], sorted[k[1]]), k[1]-k[0]),
+				it.Equal(set.CountRange(sorted[k[1]], sorted[k[0]]), 0),
+			)
+		}
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		clone := set.Clone()
+		clone.Cut(sorted[0])
+
+		has := set.Has(sorted[0])
+		it.Then(t).Should(
+			it.True(has),
+			it.Equal(set.Length(), len(sorted)),
+			it.Equal(clone.Length(), len(sorted)-1),
+		)
+
+		values := clone.Values()
+		for i := 1; i < len(sorted); i++ {
+			it.Then(t).Should(
+				it.Equal(values.Key, sorted[i]),
+				it.Equal(clone.At(i-1).Key, sorted[i]),
+			)
+			values = values.Next()
+		}
+	})
 
+	t.Run("Cut", func(t *testing.T) {
 		for _, el := range seq {
 			it.Then(t).Should(
-				it.True(f(set.Cut(el))),
+				it.True(set.Cut(el)),
 			).ShouldNot(
-				it.True(f(set.Cut(el))),
+				it.True(set.Cut(el)),
 			)
 		}
 
 		it.Then(t).Should(it.Equal(set.Length(), 0))
 	})
 
+	t.Run("CutRange", func(t *testing.T) {
+		for _, k := range [][]int{
+			{0, 0},
+			{0, len(sorted) / 4},
+			{len(sorted) / 4, len(sorted) / 2},
+			{len(sorted) / 2, len(sorted) - 1},
+			{0, len(sorted) - 1},
+		} {
+			head := skiplist.NewSet[K]()
+			for _, x := range seq {
+				head.Add(x)
+			}
+
+			it.Then(t).Should(
+				it.Equal(head.CutRange(sorted[k[0]], sorted[k[1]]), k[1]-k[0]),
+				it.Equal(head.Length(), len(sorted)-k[1]+k[0]),
+			)
+
+			i, pos := 0, 0
+			for e := head.Values(); e != nil; e = e.Next() {
+				if i == k[0] {
+					i = k[1]
+				}
+				it.Then(t).Should(
+					it.Equal(e.Key, sorted[i]),
+					it.Equal(head.At(pos).Key, sorted[i]),
+				)
+				i++
+				pos++
+			}
+			it.Then(t).Should(it.Equal(i, len(sorted)))
+		}
+	})
+
 	t.Run("Split", func(t *testing.T) {
 		for _, k := range []int{0, len(sorted) / 4, len(sorted) / 2, len(sorted) - 1} {
 			head := skiplist.NewSet[K]()
@@ -132,6 +242,7 @@ func SetSuite[K skiplist.Key](t *testing.T, seq []K) {
 			for i := 0; i < k; i++ {
 				it.Then(t).Should(
 					it.Equal(hval.Key, sorted[i]),
+					it.Equal(head.At(i).Key, sorted[i]),
 				)
 				hval = hval.Next()
 			}
@@ -140,12 +251,235 @@ func SetSuite[K skiplist.Key](t *testing.T, seq []K) {
 			for i := k; i < len(sorted); i++ {
 				it.Then(t).Should(
 					it.Equal(tval.Key, sorted[i]),
+					it.Equal(tail.At(i-k).Key, sorted[i]),
 				)
 				tval = tval.Next()
 			}
 		}
 	})
 
+	t.Run("Join", func(t *testing.T) {
+		for _, k := range []int{0, len(sorted) / 4, len(sorted) / 2, len(sorted) - 1} {
+			head := skiplist.NewSet[K]()
+			for _, x := range seq {
+				head.Add(x)
+			}
+			tail := head.Split(sorted[k])
+
+			if k != 0 {
+				it.Then(t).ShouldNot(
+					it.True(tail.Join(head)),
+				)
+			}
+
+			it.Then(t).Should(
+				it.True(head.Join(tail)),
+				it.Equal(head.Length(), len(sorted)),
+				it.Equal(tail.Length(), 0),
+			)
+
+			values := head.Values()
+			for i := 0; i < len(sorted); i++ {
+				rank, _ := head.RankOf(sorted[i])
+				it.Then(t).Should(
+					it.Equal(values.Key, sorted[i]),
+					it.Equal(head.At(i).Key, sorted[i]),
+					it.Equal(rank, i),
+				)
+				values = values.Next()
+			}
+		}
+	})
+}
+
+func TestSetWith(t *testing.T) {
+	set := skiplist.NewSetWith[string](
+		ord.From[string](func(a, b string) int {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		}),
+	)
+
+	set.AddAll("b", "A", "a", "C")
+
+	has := set.Has("B")
+	it.Then(t).Should(
+		it.True(has),
+		it.Equal(set.Length(), 3),
+		it.Equal(set.At(0).Key, "A"),
+		it.Equal(set.At(2).Key, "C"),
+	)
+}
+
+func TestSetDescending(t *testing.T) {
+	set := skiplist.NewSet(skiplist.SetWithDescending[int]())
+	set.AddAll(1, 3, 2)
+
+	it.Then(t).Should(
+		it.Equal(set.At(0).Key, 3),
+		it.Equal(set.At(1).Key, 2),
+		it.Equal(set.At(2).Key, 1),
+	)
+}
+
+func TestSetFromSorted(t *testing.T) {
+	keys := make([]int, 1000)
+	for i := 0; i < len(keys); i++ {
+		keys[i] = 2 * i
+	}
+
+	set := skiplist.NewSetFromSorted(keys)
+	it.Then(t).Should(
+		it.Equal(set.Length(), len(keys)),
+		it.Greater(set.Level(), 0),
+	)
+
+	for i := 0; i < len(keys); i++ {
+		has := set.Has(2 * i)
+		rank, _ := set.RankOf(2 * i)
+		it.Then(t).Should(
+			it.True(has),
+			it.Equal(rank, i),
+			it.Equal(set.At(i).Key, 2*i),
+		)
+	}
+
+	set.Add(1)
+	set.Cut(2)
+	it.Then(t).Should(
+		it.Equal(set.Length(), len(keys)),
+		it.Equal(set.At(1).Key, 1),
+		it.Equal(set.At(2).Key, 4),
+	)
+
+	// set and map share levels of sorted builder
+	pairs := make([]skiplist.Pair[int, int], len(keys))
+	for i, key := range keys {
+		pairs[i] = skiplist.Pair[int, int]{Key: key}
+	}
+
+	set = skiplist.NewSetFromSorted(keys)
+	kv := skiplist.NewMapFromSorted(pairs)
+	for el, e := set.Values(), kv.Values(); el != nil; el, e = el.Next(), e.Next() {
+		it.Then(t).Should(
+			it.Equal(el.Key, e.Key),
+			it.Equal(len(el.Fingers), len(e.Fingers)),
+		)
+	}
+	it.Then(t).Should(it.Equal(set.Level(), kv.Level()))
+}
+
+func TestSetSubset(t *testing.T) {
+	a := skiplist.NewSet[int]()
+	a.AddAll(1, 2, 3, 4, 5, 6)
+
+	b := skiplist.NewSet[int]()
+	b.AddAll(2, 4, 6)
+
+	c := skiplist.NewSet[int]()
+	c.AddAll(7, 8, 9)
+
+	d := skiplist.NewSet[int]()
+
+	it.Then(t).Should(
+		it.True(b.IsSubset(a)),
+		it.True(a.IsSuperset(b)),
+		it.True(a.IsSubset(a)),
+		it.True(d.IsSubset(a)),
+		it.True(a.Disjoint(c)),
+		it.True(a.Disjoint(d)),
+	).ShouldNot(
+		it.True(a.IsSubset(b)),
+		it.True(b.IsSuperset(a)),
+		it.True(c.IsSubset(a)),
+		it.True(a.Disjoint(b)),
+	)
+}
+
+func TestSetEqual(t *testing.T) {
+	a := skiplist.NewSet[int]()
+	a.AddAll(1, 2, 3)
+
+	b := skiplist.NewSet[int]()
+	b.AddAll(3, 2, 1)
+
+	c := skiplist.NewSet[int]()
+	c.AddAll(1, 2, 4)
+
+	it.Then(t).Should(
+		it.True(a.Equal(b)),
+		it.True(a.Equal(a.Clone())),
+	).ShouldNot(
+		it.True(a.Equal(c)),
+		it.True(a.Equal(skiplist.NewSet[int]())),
+	)
+}
+
+type setAllocator struct{ alloc, free int }
+
+func (a *setAllocator) Alloc(key int) *skiplist.Element[int] {
+	a.alloc++
+	return &skiplist.Element[int]{Fingers: make([]*skiplist.Element[int], skiplist.L)}
+}
+
+func (a *setAllocator) Free(key int) { a.free++ }
+
+func TestSetClear(t *testing.T) {
+	malloc := &setAllocator{}
+	set := skiplist.NewSet(skiplist.SetWithAllocator[int](malloc))
+	set.AddAll(1, 2, 3, 4, 5)
+	set.Clear()
+
+	it.Then(t).Should(
+		it.Equal(set.Length(), 0),
+		it.True(set.Values() == nil),
+		it.Equal(malloc.alloc, 5),
+		it.Equal(malloc.free, 5),
+	)
+
+	set.AddAll(3, 2, 1)
+	it.Then(t).Should(
+		it.Equal(set.Length(), 3),
+		it.Equal(set.At(0).Key, 1),
+		it.Equal(malloc.alloc, 8),
+	)
+}
+
+type nodeAllocator struct {
+	setAllocator
+	live map[*skiplist.Element[int]]int
+}
+
+func (a *nodeAllocator) AllocNode(key int, level int) *skiplist.Element[int] {
+	el := &skiplist.Element[int]{Fingers: make([]*skiplist.Element[int], level)}
+	a.live[el] = level
+	return el
+}
+
+func (a *nodeAllocator) FreeNode(el *skiplist.Element[int]) { delete(a.live, el) }
+
+func TestSetNodeAllocator(t *testing.T) {
+	malloc := &nodeAllocator{live: map[*skiplist.Element[int]]int{}}
+	set := skiplist.NewSet(skiplist.SetWithAllocator[int](malloc))
+	for i := 0; i < 100; i++ {
+		set.Add(i)
+	}
+
+	for e, level := range malloc.live {
+		it.Then(t).Should(it.Equal(e.Rank(), level))
+	}
+
+	it.Then(t).Should(it.Equal(len(malloc.live), 100))
+
+	set.Cut(50)
+	set.CutRange(0, 10)
+	it.Then(t).Should(
+		it.Equal(len(malloc.live), 89),
+		it.Equal(malloc.alloc, 0),
+		it.Equal(malloc.free, 0),
+	)
+
+	set.Clear()
+	it.Then(t).Should(it.Equal(len(malloc.live), 0))
 }
 
 func TestSetOfIntAddHasCut(t *testing.T) {
@@ -285,8 +619,32 @@ func FuzzSetAddHas(f *testing.F) {
 
 	f.Fuzz(func(t *testing.T, el string) {
 		set.Add(el)
-		if has, _ := set.Has(el); !has {
+		if has := set.Has(el); !has {
 			t.Errorf("element %s should be found", el)
 		}
 	})
 }
+
+func TestSetParallelReads(t *testing.T) {
+	set := skiplist.NewSet[int]()
+	for i := 0; i < 1000; i++ {
+		set.Add(i)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				has := set.Has(i)
+				it.Then(t).Should(
+					it.True(has),
+					it.Equal(set.Successor(i).Key, i),
+					it.Equal(set.CountRange(0, i), i),
+				)
+			}
+		}()
+	}
+	wg.Wait()
+}
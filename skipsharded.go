@@ -0,0 +1,166 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"sort"
+	"sync"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// ShardedMap partitions key space into ranges, each range is served by
+// own skip map guarded by own lock. Writes to different shards do not
+// contend. Shards are ordered, the ordered iteration visits shards one by one.
+type ShardedMap[K any, V any] struct {
+	bounds  []K
+	shards  []*shard[K, V]
+	compare func(K, K) int
+}
+
+type shard[K any, V any] struct {
+	sync.RWMutex
+	kv *Map[K, V]
+}
+
+// NewShardedMap creates instance of sharded map. The bounds are sorted lower
+// bounds of shards, n bounds makes n+1 shards: (-∞, b0), [b0, b1), ... [bn, +∞).
+// Config options are applied to each shard, the random source (if any)
+// must be safe for concurrent use.
+func NewShardedMap[K Key, V any](bounds []K, opts ...MapConfig[K, V]) *ShardedMap[K, V] {
+	return NewShardedMapWith[K, V](ord.Type[K]{}, bounds, opts...)
+}
+
+// NewShardedMapWith creates instance of sharded map, keys are ordered by
+// the given type class.
+func NewShardedMapWith[K any, V any](cmp ord.Ord[K], bounds []K, opts ...MapConfig[K, V]) *ShardedMap[K, V] {
+	for i := 1; i < len(bounds); i++ {
+		if cmp.Compare(bounds[i-1], bounds[i]) >= 0 {
+			panic("bounds are not sorted")
+		}
+	}
+
+	shards := make([]*shard[K, V], len(bounds)+1)
+	for i := range shards {
+		shards[i] = &shard[K, V]{kv: NewMapWith(cmp, opts...)}
+	}
+
+	return &ShardedMap[K, V]{
+		bounds:  append([]K(nil), bounds...),
+		shards:  shards,
+		compare: cmp.Compare,
+	}
+}
+
+// index of shard that owns the key
+func (kv *ShardedMap[K, V]) shardAt(key K) int {
+	return sort.Search(len(kv.bounds), func(i int) bool {
+		return kv.compare(kv.bounds[i], key) > 0
+	})
+}
+
+func (kv *ShardedMap[K, V]) shardOf(key K) *shard[K, V] {
+	return kv.shards[kv.shardAt(key)]
+}
+
+// Shards returns number of shards
+func (kv *ShardedMap[K, V]) Shards() int {
+	return len(kv.shards)
+}
+
+// Length of the map, shards are counted one by one, the value is
+// approximate while writes are in progress.
+func (kv *ShardedMap[K, V]) Length() int {
+	n := 0
+	for _, s := range kv.shards {
+		s.RLock()
+		n += s.kv.Length()
+		s.RUnlock()
+	}
+
+	return n
+}
+
+// Put key-value pair into map, returns true if pair is new
+func (kv *ShardedMap[K, V]) Put(key K, val V) bool {
+	s := kv.shardOf(key)
+	s.Lock()
+	defer s.Unlock()
+
+	return s.kv.Put(key, val)
+}
+
+// Get value of the key
+func (kv *ShardedMap[K, V]) Get(key K) (V, bool) {
+	s := kv.shardOf(key)
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.kv.Get(key)
+}
+
+// Compute performs read-modify-write of the value atomically, see Map.Compute
+func (kv *ShardedMap[K, V]) Compute(key K, f func(V, bool) (V, bool)) (V, bool) {
+	s := kv.shardOf(key)
+	s.Lock()
+	defer s.Unlock()
+
+	return s.kv.Compute(key, f)
+}
+
+// Cut key from the map, returns removed value and true if key existed
+func (kv *ShardedMap[K, V]) Cut(key K) (V, bool) {
+	s := kv.shardOf(key)
+	s.Lock()
+	defer s.Unlock()
+
+	return s.kv.Cut(key)
+}
+
+// All pairs of the map in key order. The read lock of shard is held while
+// loop visits the shard, the loop body must not write to the map.
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *ShardedMap[K, V]) All() iter.Seq2[K, V] {
+	return kv.ascend(0, func(m *Map[K, V]) *Pair[K, V] { return m.Values() })
+}
+
+// Ascend iterates over pairs of the map starting from the key. The read lock
+// of shard is held while loop visits the shard, the loop body must not write
+// to the map.
+//
+//	for k, v := range kv.Ascend(key) { /* ... */ }
+func (kv *ShardedMap[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return kv.ascend(kv.shardAt(from), func(m *Map[K, V]) *Pair[K, V] { return m.Successor(from) })
+}
+
+func (kv *ShardedMap[K, V]) ascend(at int, first func(*Map[K, V]) *Pair[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for i := at; i < len(kv.shards); i++ {
+			if !kv.visit(kv.shards[i], first, yield) {
+				return
+			}
+			first = func(m *Map[K, V]) *Pair[K, V] { return m.Values() }
+		}
+	}
+}
+
+func (kv *ShardedMap[K, V]) visit(s *shard[K, V], first func(*Map[K, V]) *Pair[K, V], yield func(K, V) bool) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	for e := first(s.kv); e != nil; e = e.Fingers[0] {
+		if !yield(e.Key, e.Value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,60 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestShardedMap(t *testing.T) {
+	kv := skiplist.NewShardedMap[int, int]([]int{250, 500, 750})
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < 1000; i += 8 {
+				kv.Put(i, i)
+				kv.Get(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	keys := []int{}
+	for k, v := range kv.All() {
+		it.Then(t).Should(it.Equal(k, v))
+		keys = append(keys, k)
+	}
+
+	from := []int{}
+	for k := range kv.Ascend(495) {
+		from = append(from, k)
+		if len(from) == 10 {
+			break
+		}
+	}
+
+	val, has := kv.Cut(500)
+
+	it.Then(t).Should(
+		it.Equal(kv.Shards(), 4),
+		it.Equal(len(keys), 1000),
+		it.Seq(keys[245:255]).Equal(245, 246, 247, 248, 249, 250, 251, 252, 253, 254),
+		it.Seq(from).Equal(495, 496, 497, 498, 499, 500, 501, 502, 503, 504),
+		it.Equal(val, 500),
+		it.True(has),
+		it.Equal(kv.Length(), 999),
+	)
+}
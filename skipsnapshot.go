@@ -0,0 +1,98 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import "iter"
+
+// Snapshot is immutable view of the map at the point of time. The snapshot
+// shares nodes with the map until the next write, the first write after
+// the snapshot is taken copies the whole map in O(n), nodes are not copied
+// individually. The snapshot is safe to read from other goroutines while
+// the map receives writes. Use PersistentMap if snapshots are taken
+// frequently, it copies nodes on the path of the write only.
+type Snapshot[K any, V any] struct {
+	kv *Map[K, V]
+}
+
+// Snapshot takes immutable view of the map in O(1). The first write to
+// the map after the snapshot copies the whole map in O(n).
+func (kv *Map[K, V]) Snapshot() *Snapshot[K, V] {
+	kv.shared = true
+
+	view := *kv
+	view.malloc = nil
+	view.finger = nil
+	view.compare = kv.ordering()
+	view.probe = nil
+	view.unprobed = nil
+
+	return &Snapshot[K, V]{kv: &view}
+}
+
+// detach the map from nodes shared with snapshot
+func (kv *Map[K, V]) detach() {
+	if !kv.shared {
+		return
+	}
+
+	// nodes of snapshot are never returned to allocator
+	malloc := kv.malloc
+	kv.malloc = nil
+	clone := kv.Clone()
+	kv.malloc = malloc
+
+	kv.head = clone.head
+	kv.shared = false
+	kv.finger.reset()
+}
+
+func (snap *Snapshot[K, V]) String() string { return snap.kv.String() }
+
+func (snap *Snapshot[K, V]) Length() int { return snap.kv.Length() }
+
+// Get value of the key
+func (snap *Snapshot[K, V]) Get(key K) (V, bool) {
+	return snap.kv.Get(key)
+}
+
+// All pairs of the snapshot, the pairs must not be modified.
+func (snap *Snapshot[K, V]) Values() *Pair[K, V] { return snap.kv.Values() }
+
+// Successor pair of the key, the pair must not be modified.
+func (snap *Snapshot[K, V]) Successor(key K) *Pair[K, V] { return snap.kv.Successor(key) }
+
+// At returns pair at position i, the pair must not be modified.
+func (snap *Snapshot[K, V]) At(i int) *Pair[K, V] { return snap.kv.At(i) }
+
+// RankOf returns position of the key, see Map.RankOf
+func (snap *Snapshot[K, V]) RankOf(key K) (int, bool) { return snap.kv.RankOf(key) }
+
+// CountRange returns number of keys in range [from, to)
+func (snap *Snapshot[K, V]) CountRange(from, to K) int { return snap.kv.CountRange(from, to) }
+
+// KeysSlice returns ordered slice of all keys
+func (snap *Snapshot[K, V]) KeysSlice() []K { return snap.kv.KeysSlice() }
+
+// ValuesSlice returns slice of all values ordered by keys
+func (snap *Snapshot[K, V]) ValuesSlice() []V { return snap.kv.ValuesSlice() }
+
+// All pairs of the snapshot in key order
+//
+//	for k, v := range snap.All() { /* ... */ }
+func (snap *Snapshot[K, V]) All() iter.Seq2[K, V] { return snap.kv.All() }
+
+// Keys of the snapshot in order
+//
+//	for k := range snap.Keys() { /* ... */ }
+func (snap *Snapshot[K, V]) Keys() iter.Seq[K] { return snap.kv.Keys() }
+
+// Ascend iterates over pairs of the snapshot starting from the key
+//
+//	for k, v := range snap.Ascend(key) { /* ... */ }
+func (snap *Snapshot[K, V]) Ascend(from K) iter.Seq2[K, V] { return snap.kv.Ascend(from) }
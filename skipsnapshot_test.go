@@ -0,0 +1,85 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestSnapshot(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		kv.Put(i, i)
+	}
+
+	snap := kv.Snapshot()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			kv.Put(i, -i)
+			kv.Put(i+1000, i)
+			if i%2 == 0 {
+				kv.Cut(i)
+			}
+		}
+	}()
+
+	n := 0
+	for k, v := range snap.All() {
+		it.Then(t).Should(
+			it.Equal(k, n),
+			it.Equal(v, n),
+		)
+		n++
+	}
+	wg.Wait()
+
+	val, has := snap.Get(10)
+	it.Then(t).Should(
+		it.Equal(n, 1000),
+		it.Equal(snap.Length(), 1000),
+		it.Equal(kv.Length(), 1500),
+		it.Equal(val, 10),
+		it.True(has),
+		it.Equal(snap.CountRange(0, 100), 100),
+		it.Equal(kv.CountRange(0, 100), 50),
+	)
+
+	t.Run("Clear", func(t *testing.T) {
+		snap := kv.Snapshot()
+		kv.Clear()
+		kv.Put(1, 1)
+
+		it.Then(t).Should(
+			it.Equal(kv.Length(), 1),
+			it.Equal(snap.Length(), 1500),
+			it.Equal(len(snap.KeysSlice()), 1500),
+		)
+	})
+
+	t.Run("Compute", func(t *testing.T) {
+		snap := kv.Snapshot()
+		kv.Compute(1, func(v int, _ bool) (int, bool) { return v + 1, true })
+		skiplist.CompareAndSwap(kv, 1, 2, 3)
+
+		v, _ := kv.Get(1)
+		s, _ := snap.Get(1)
+		it.Then(t).Should(
+			it.Equal(v, 3),
+			it.Equal(s, 1),
+		)
+	})
+}
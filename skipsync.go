@@ -0,0 +1,488 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// SyncMap is thread-safe Map guarded by read/write mutex. Methods return
+// copies of keys and values instead of pointers to internal nodes, which
+// are not safe to access outside of the lock.
+type SyncMap[K any, V any] struct {
+	mu sync.RWMutex
+	kv *Map[K, V]
+}
+
+// NewSyncMap creates instance of thread-safe Map
+func NewSyncMap[K Key, V any](opts ...MapConfig[K, V]) *SyncMap[K, V] {
+	return &SyncMap[K, V]{kv: NewMap(opts...)}
+}
+
+// NewSyncMapWith creates instance of thread-safe Map, keys are ordered by
+// the given type class
+func NewSyncMapWith[K any, V any](cmp ord.Ord[K], opts ...MapConfig[K, V]) *SyncMap[K, V] {
+	return &SyncMap[K, V]{kv: NewMapWith(cmp, opts...)}
+}
+
+func (kv *SyncMap[K, V]) String() string {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	return kv.kv.String()
+}
+
+func (kv *SyncMap[K, V]) Length() int {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	return kv.kv.Length()
+}
+
+// Put key-value pair into map, returns true if pair is new
+func (kv *SyncMap[K, V]) Put(key K, val V) bool {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	return kv.kv.Put(key, val)
+}
+
+// PutAll puts pairs into map, returns number of new pairs
+func (kv *SyncMap[K, V]) PutAll(pairs ...Pair[K, V]) int {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	return kv.kv.PutAll(pairs...)
+}
+
+// Get value of the key
+func (kv *SyncMap[K, V]) Get(key K) (V, bool) {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	return kv.kv.Get(key)
+}
+
+// GetOrInsert returns existing value and true if key exists, otherwise puts
+// the value and returns it with false
+func (kv *SyncMap[K, V]) GetOrInsert(key K, val V) (V, bool) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	return kv.kv.GetOrInsert(key, val)
+}
+
+// Compute performs read-modify-write of the value atomically, see Map.Compute
+func (kv *SyncMap[K, V]) Compute(key K, f func(V, bool) (V, bool)) (V, bool) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	return kv.kv.Compute(key, f)
+}
+
+// Cut key from the map, returns removed value and true if key existed
+func (kv *SyncMap[K, V]) Cut(key K) (V, bool) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	return kv.kv.Cut(key)
+}
+
+// CutRange removes keys in range [from, to), returns number of removed keys
+func (kv *SyncMap[K, V]) CutRange(from, to K) int {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	return kv.kv.CutRange(from, to)
+}
+
+// Clear removes all pairs from the map
+func (kv *SyncMap[K, V]) Clear() {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	kv.kv.Clear()
+}
+
+// At returns pair at position i, pairs are indexed from 0
+func (kv *SyncMap[K, V]) At(i int) (K, V, bool) {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	el := kv.kv.At(i)
+	if el == nil {
+		return *new(K), *new(V), false
+	}
+
+	return el.Key, el.Value, true
+}
+
+// RankOf returns position of the key, see Map.RankOf
+func (kv *SyncMap[K, V]) RankOf(key K) (int, bool) {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	return kv.kv.RankOf(key)
+}
+
+// CountRange returns number of keys in range [from, to)
+func (kv *SyncMap[K, V]) CountRange(from, to K) int {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	return kv.kv.CountRange(from, to)
+}
+
+// KeysSlice returns ordered slice of all keys
+func (kv *SyncMap[K, V]) KeysSlice() []K {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	return kv.kv.KeysSlice()
+}
+
+// ValuesSlice returns slice of all values ordered by keys
+func (kv *SyncMap[K, V]) ValuesSlice() []V {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	return kv.kv.ValuesSlice()
+}
+
+// Clone returns consistent copy of the map
+func (kv *SyncMap[K, V]) Clone() *Map[K, V] {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	return kv.kv.Clone()
+}
+
+// All pairs of the map in key order. The read lock is held while loop is
+// running, the loop body must not write to the map.
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *SyncMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		kv.mu.RLock()
+		defer kv.mu.RUnlock()
+
+		kv.kv.All()(yield)
+	}
+}
+
+// Ascend iterates over pairs of the map starting from the key. The read lock
+// is held while loop is running, the loop body must not write to the map.
+//
+//	for k, v := range kv.Ascend(key) { /* ... */ }
+func (kv *SyncMap[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		kv.mu.RLock()
+		defer kv.mu.RUnlock()
+
+		kv.kv.Ascend(from)(yield)
+	}
+}
+
+// SyncSet is thread-safe Set guarded by read/write mutex.
+type SyncSet[K any] struct {
+	mu  sync.RWMutex
+	set *Set[K]
+}
+
+// NewSyncSet creates instance of thread-safe Set
+func NewSyncSet[K Key](opts ...SetConfig[K]) *SyncSet[K] {
+	return &SyncSet[K]{set: NewSet(opts...)}
+}
+
+// NewSyncSetWith creates instance of thread-safe Set, keys are ordered by
+// the given type class
+func NewSyncSetWith[K any](cmp ord.Ord[K], opts ...SetConfig[K]) *SyncSet[K] {
+	return &SyncSet[K]{set: NewSetWith(cmp, opts...)}
+}
+
+func (set *SyncSet[K]) String() string {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	return set.set.String()
+}
+
+func (set *SyncSet[K]) Length() int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	return set.set.Length()
+}
+
+// Add key to set, returns true if key is new
+func (set *SyncSet[K]) Add(key K) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	return set.set.Add(key)
+}
+
+// AddAll keys to set, returns number of new keys
+func (set *SyncSet[K]) AddAll(keys ...K) int {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	return set.set.AddAll(keys...)
+}
+
+// Has checks if key exists in set
+func (set *SyncSet[K]) Has(key K) bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	return set.set.Has(key)
+}
+
+// Cut key from the set, returns true if key is removed
+func (set *SyncSet[K]) Cut(key K) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	return set.set.Cut(key)
+}
+
+// CutRange removes keys in range [from, to), returns number of removed keys
+func (set *SyncSet[K]) CutRange(from, to K) int {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	return set.set.CutRange(from, to)
+}
+
+// Clear removes all keys from the set
+func (set *SyncSet[K]) Clear() {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	set.set.Clear()
+}
+
+// At returns key at position i, keys are indexed from 0
+func (set *SyncSet[K]) At(i int) (K, bool) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	el := set.set.At(i)
+	if el == nil {
+		return *new(K), false
+	}
+
+	return el.Key, true
+}
+
+// RankOf returns position of the key, see Set.RankOf
+func (set *SyncSet[K]) RankOf(key K) (int, bool) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	return set.set.RankOf(key)
+}
+
+// CountRange returns number of keys in range [from, to)
+func (set *SyncSet[K]) CountRange(from, to K) int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	return set.set.CountRange(from, to)
+}
+
+// Clone returns consistent copy of the set
+func (set *SyncSet[K]) Clone() *Set[K] {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	return set.set.Clone()
+}
+
+// All keys of the set in order. The read lock is held while loop is running,
+// the loop body must not write to the set.
+//
+//	for k := range set.All() { /* ... */ }
+func (set *SyncSet[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		set.mu.RLock()
+		defer set.mu.RUnlock()
+
+		set.set.All()(yield)
+	}
+}
+
+// Ascend iterates over keys of the set starting from the key. The read lock
+// is held while loop is running, the loop body must not write to the set.
+//
+//	for k := range set.Ascend(key) { /* ... */ }
+func (set *SyncSet[K]) Ascend(from K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		set.mu.RLock()
+		defer set.mu.RUnlock()
+
+		set.set.Ascend(from)(yield)
+	}
+}
+
+// SyncHashMap is thread-safe HashMap. Values are kept in sync.Map, Get is
+// lock-free. Only mutations of the ordered index of keys take the lock,
+// ordered reads hold the read lock. The map suits read-heavy workloads.
+type SyncHashMap[K comparable, V any] struct {
+	mu     sync.RWMutex
+	keys   *Set[K]
+	values sync.Map
+
+	// equality of type class is == operator, see HashMap
+	exact bool
+}
+
+// NewSyncHashMap creates instance of thread-safe HashMap
+func NewSyncHashMap[K Key, V any](opts ...SetConfig[K]) *SyncHashMap[K, V] {
+	kv := NewSyncHashMapWith[K, V](ord.Type[K]{}, opts...)
+	kv.exact = true
+	return kv
+}
+
+// NewSyncHashMapWith creates instance of thread-safe HashMap, keys are
+// ordered by the given type class
+func NewSyncHashMapWith[K comparable, V any](cmp ord.Ord[K], opts ...SetConfig[K]) *SyncHashMap[K, V] {
+	return &SyncHashMap[K, V]{keys: NewSetWith(cmp, opts...)}
+}
+
+func (kv *SyncHashMap[K, V]) Length() int {
+	kv.mu.RLock()
+	defer kv.mu.RUnlock()
+
+	return kv.keys.Length()
+}
+
+// canonical key stored by the map, see HashMap. The lock must be held.
+func (kv *SyncHashMap[K, V]) canonical(key K) (K, bool) {
+	if _, has := kv.values.Load(key); has || kv.exact {
+		return key, has
+	}
+
+	if el := kv.keys.Find(key); el != nil {
+		return el.Key, true
+	}
+
+	return key, false
+}
+
+// Put key-value pair into map, returns true if pair is new
+func (kv *SyncHashMap[K, V]) Put(key K, val V) bool {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	key, has := kv.canonical(key)
+	kv.values.Store(key, val)
+	if has {
+		return false
+	}
+
+	kv.keys.Add(key)
+	return true
+}
+
+// Get value of the key, it does not take the lock unless the key is equal
+// to the stored one by type class only
+func (kv *SyncHashMap[K, V]) Get(key K) (V, bool) {
+	val, has := kv.values.Load(key)
+	if !has && kv.exact {
+		return *new(V), false
+	}
+
+	if !has {
+		kv.mu.RLock()
+		defer kv.mu.RUnlock()
+
+		el := kv.keys.Find(key)
+		if el == nil {
+			return *new(V), false
+		}
+
+		val, _ = kv.values.Load(el.Key)
+	}
+
+	v, _ := val.(V)
+	return v, true
+}
+
+// GetOrInsert returns existing value and true if key exists, otherwise puts
+// the value and returns it with false
+func (kv *SyncHashMap[K, V]) GetOrInsert(key K, val V) (V, bool) {
+	if v, has := kv.values.Load(key); has {
+		x, _ := v.(V)
+		return x, true
+	}
+
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	if key, has := kv.canonical(key); has {
+		v, _ := kv.values.Load(key)
+		x, _ := v.(V)
+		return x, true
+	}
+
+	kv.values.Store(key, val)
+	kv.keys.Add(key)
+	return val, false
+}
+
+// Cut key from the map, returns the value and true if key is removed
+func (kv *SyncHashMap[K, V]) Cut(key K) (V, bool) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	key, has := kv.canonical(key)
+	if !has {
+		return *new(V), false
+	}
+
+	val, _ := kv.values.LoadAndDelete(key)
+	kv.keys.Cut(key)
+	v, _ := val.(V)
+	return v, true
+}
+
+// All pairs of the map in key order. The read lock is held while loop is
+// running, the loop body must not write to the map.
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *SyncHashMap[K, V]) All() iter.Seq2[K, V] {
+	return kv.ascend(func() *Element[K] { return kv.keys.Values() })
+}
+
+// Ascend iterates over pairs of the map starting from the key. The read lock
+// is held while loop is running, the loop body must not write to the map.
+//
+//	for k, v := range kv.Ascend(key) { /* ... */ }
+func (kv *SyncHashMap[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return kv.ascend(func() *Element[K] { return kv.keys.Successor(from) })
+}
+
+func (kv *SyncHashMap[K, V]) ascend(seek func() *Element[K]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		kv.mu.RLock()
+		defer kv.mu.RUnlock()
+
+		for e := seek(); e != nil; e = e.Fingers[0] {
+			val, _ := kv.values.Load(e.Key)
+			v, _ := val.(V)
+			if !yield(e.Key, v) {
+				return
+			}
+		}
+	}
+}
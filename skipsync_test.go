@@ -0,0 +1,203 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+	"github.com/fogfish/skiplist/ord"
+)
+
+func TestSyncMap(t *testing.T) {
+	kv := skiplist.NewSyncMap[int, int]()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < 1000; i += 8 {
+				kv.Put(i, i)
+				kv.Get(i)
+				kv.Compute(-1, func(v int, _ bool) (int, bool) { return v + 1, true })
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	n := 0
+	for k, v := range kv.All() {
+		if k >= 0 {
+			it.Then(t).Should(it.Equal(k, v))
+		}
+		n++
+	}
+
+	cnt, _ := kv.Get(-1)
+	val, has := kv.Cut(10)
+	_, at, ok := kv.At(1)
+
+	it.Then(t).Should(
+		it.Equal(n, 1001),
+		it.Equal(cnt, 1000),
+		it.Equal(val, 10),
+		it.True(has),
+		it.True(ok),
+		it.Equal(at, 0),
+		it.Equal(kv.Length(), 1000),
+		it.Equal(kv.CountRange(0, 100), 99),
+	)
+}
+
+func TestSyncMapFingerSearch(t *testing.T) {
+	kv := skiplist.NewSyncMap(skiplist.MapWithFingerSearch[int, int]())
+	for i := 0; i < 1000; i++ {
+		kv.Put(i, i)
+	}
+
+	// readers share the finger, run with -race
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < 1000; i += 8 {
+				v, has := kv.Get(i)
+				it.Then(t).Should(it.True(has), it.Equal(v, i))
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestSyncSet(t *testing.T) {
+	set := skiplist.NewSyncSet[int]()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < 1000; i += 8 {
+				set.Add(i)
+				set.Has(i)
+				for range set.Ascend(i) {
+					break
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	n := 0
+	for range set.All() {
+		n++
+	}
+
+	it.Then(t).Should(
+		it.Equal(n, 1000),
+		it.Equal(set.Length(), 1000),
+		it.True(set.Cut(500)),
+		it.Equal(set.CutRange(0, 100), 100),
+		it.Equal(set.Length(), 899),
+	).ShouldNot(
+		it.True(set.Has(500)),
+	)
+}
+
+func TestSyncHashMap(t *testing.T) {
+	kv := skiplist.NewSyncHashMap[int, int]()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < 1000; i += 8 {
+				kv.Put(i, i)
+				kv.Get(i - 8)
+				kv.GetOrInsert(i+1000, i)
+				kv.Cut(i + 1000)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	prev, n := -1, 0
+	for k, v := range kv.All() {
+		it.Then(t).Should(it.Less(prev, k), it.Equal(k, v))
+		prev = k
+		n++
+	}
+
+	_, had5 := kv.GetOrInsert(5, 0)
+	val, has := kv.Cut(10)
+	_, had := kv.Get(10)
+	from := []int{}
+	for k := range kv.Ascend(997) {
+		from = append(from, k)
+	}
+
+	it.Then(t).Should(
+		it.Equal(n, 1000),
+		it.True(had5),
+		it.True(has),
+		it.Equal(val, 10),
+		it.True(!had),
+		it.Equal(kv.Length(), 999),
+		it.Seq(from).Equal(997, 998, 999),
+	)
+}
+
+func TestSyncHashMapNilInterface(t *testing.T) {
+	kv := skiplist.NewSyncHashMap[string, error]()
+	kv.Put("a", nil)
+
+	val, has := kv.Get("a")
+	ins, _ := kv.GetOrInsert("a", nil)
+	seq := []error{}
+	for _, v := range kv.All() {
+		seq = append(seq, v)
+	}
+	cut, had := kv.Cut("a")
+
+	it.Then(t).Should(
+		it.True(has),
+		it.True(val == nil),
+		it.True(ins == nil),
+		it.Equal(len(seq), 1),
+		it.True(had),
+		it.True(cut == nil),
+	)
+}
+
+func TestSyncHashMapTimeLocation(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	kv := skiplist.NewSyncHashMapWith[time.Time, int](ord.Time)
+
+	kv.Put(at, 1)
+	isAlt := kv.Put(at.UTC(), 2)
+	val, has := kv.Get(at.UTC())
+	cut, had := kv.Cut(at.UTC())
+	_, left := kv.Get(at)
+
+	it.Then(t).Should(
+		it.True(!isAlt),
+		it.True(has),
+		it.Equal(val, 2),
+		it.True(had),
+		it.Equal(cut, 2),
+		it.Equal(kv.Length(), 0),
+		it.True(!left),
+	)
+}
@@ -0,0 +1,114 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// TombstoneMap is a map with lazy deletion. Cut marks the pair as deleted
+// (tombstone) without unlinking it, Put of the deleted key revives the pair
+// in place. Compact removes tombstones in a single pass. The map benefits
+// bursty delete-then-reinsert workloads.
+type TombstoneMap[K any, V any] struct {
+	kv   *Map[K, tombstone[V]]
+	dead int
+}
+
+// value of the map, removed flag denotes a tombstone
+type tombstone[V any] struct {
+	value   V
+	removed bool
+}
+
+// NewTombstoneMap creates instance of map with lazy deletion
+func NewTombstoneMap[K Key, V any]() *TombstoneMap[K, V] {
+	return NewTombstoneMapWith[K, V](ord.Type[K]{})
+}
+
+// NewTombstoneMapWith creates instance of map with lazy deletion, keys are
+// ordered by the given type class
+func NewTombstoneMapWith[K any, V any](cmp ord.Ord[K]) *TombstoneMap[K, V] {
+	return &TombstoneMap[K, V]{kv: NewMapWith[K, tombstone[V]](cmp)}
+}
+
+// Length returns number of live keys
+func (kv *TombstoneMap[K, V]) Length() int {
+	return kv.kv.Length() - kv.dead
+}
+
+// Tombstones returns number of deleted keys awaiting compaction
+func (kv *TombstoneMap[K, V]) Tombstones() int {
+	return kv.dead
+}
+
+// Put value of the key, returns true if key is new or revived
+func (kv *TombstoneMap[K, V]) Put(key K, val V) bool {
+	isNew, el := kv.kv.getOrInsert(key, tombstone[V]{value: val})
+	if isNew {
+		return true
+	}
+
+	if el.Value.removed {
+		kv.dead--
+		isNew = true
+	}
+
+	el.Value = tombstone[V]{value: val}
+	return isNew
+}
+
+// Get value of the key
+func (kv *TombstoneMap[K, V]) Get(key K) (V, bool) {
+	val, has := kv.kv.Get(key)
+	if !has || val.removed {
+		return *new(V), false
+	}
+
+	return val.value, true
+}
+
+// Cut marks the key as deleted, returns the value and true if key is live
+func (kv *TombstoneMap[K, V]) Cut(key K) (V, bool) {
+	el := kv.kv.Find(key)
+	if el == nil || el.Value.removed {
+		return *new(V), false
+	}
+
+	val := el.Value
+	el.Value = tombstone[V]{removed: true}
+	kv.dead++
+
+	return val.value, true
+}
+
+// Compact unlinks all tombstones in O(n), returns number of removed keys
+func (kv *TombstoneMap[K, V]) Compact() int {
+	n := kv.kv.sweep(func(e *Pair[K, tombstone[V]]) bool { return e.Value.removed })
+	kv.dead = 0
+
+	return n
+}
+
+// All live pairs in key order
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *TombstoneMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := kv.kv.Values(); e != nil; e = e.Next() {
+			if !e.Value.removed {
+				if !yield(e.Key, e.Value.value) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestTombstoneMap(t *testing.T) {
+	kv := skiplist.NewTombstoneMap[int, string]()
+	for i := 0; i < 100; i++ {
+		it.Then(t).Should(it.True(kv.Put(i, "x")))
+	}
+
+	for i := 0; i < 100; i += 2 {
+		val, has := kv.Cut(i)
+		_, again := kv.Cut(i)
+		it.Then(t).Should(
+			it.True(has),
+			it.Equal(val, "x"),
+			it.True(!again),
+		)
+	}
+
+	_, has := kv.Get(10)
+	it.Then(t).Should(
+		it.True(!has),
+		it.Equal(kv.Length(), 50),
+		it.Equal(kv.Tombstones(), 50),
+	)
+
+	it.Then(t).Should(
+		it.True(kv.Put(10, "y")),
+		it.True(!kv.Put(10, "z")),
+		it.Equal(kv.Length(), 51),
+	)
+
+	t.Run("All", func(t *testing.T) {
+		n := 0
+		for k, v := range kv.All() {
+			it.Then(t).Should(it.True(k%2 == 1 || (k == 10 && v == "z")))
+			n++
+		}
+		it.Then(t).Should(it.Equal(n, 51))
+	})
+
+	t.Run("Compact", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Equal(kv.Compact(), 49),
+			it.Equal(kv.Tombstones(), 0),
+			it.Equal(kv.Length(), 51),
+		)
+
+		v, has := kv.Get(10)
+		it.Then(t).Should(it.True(has), it.Equal(v, "z"))
+
+		kv.Put(0, "a")
+		kv.Cut(99)
+		keys := []int{}
+		for k := range kv.All() {
+			keys = append(keys, k)
+		}
+		it.Then(t).Should(
+			it.Equal(len(keys), 51),
+			it.Equal(keys[0], 0),
+			it.Equal(keys[2], 3),
+			it.Equal(keys[50], 97),
+		)
+	})
+}
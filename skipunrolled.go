@@ -0,0 +1,240 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// UnrolledMap is skip list of blocks, each block holds a small sorted array
+// of keys and values. The skip list indexes blocks by the first key, the
+// search within block is binary. Unrolling reduces pointer chasing and
+// improves cache locality of lookups at the cost of O(B) insert into block.
+type UnrolledMap[K any, V any] struct {
+	head    *ublock[K, V]
+	length  int
+	size    int
+	random  rand.Source
+	compare func(K, K) int
+}
+
+// block of unrolled map, keys are sorted and non-empty for any block
+// except the head.
+type ublock[K any, V any] struct {
+	keys    []K
+	values  []V
+	fingers []*ublock[K, V]
+}
+
+// NewUnrolledMap creates unrolled map, each block holds up to size pairs
+func NewUnrolledMap[K Key, V any](size int) *UnrolledMap[K, V] {
+	return NewUnrolledMapWith[K, V](ord.Type[K]{}, size)
+}
+
+// NewUnrolledMapWith creates unrolled map, keys are ordered by the given
+// type class, each block holds up to size pairs
+func NewUnrolledMapWith[K any, V any](cmp ord.Ord[K], size int) *UnrolledMap[K, V] {
+	if size < 2 {
+		size = 2
+	}
+
+	return &UnrolledMap[K, V]{
+		head:    &ublock[K, V]{fingers: make([]*ublock[K, V], L)},
+		size:    size,
+		random:  rand.NewSource(time.Now().UnixNano()),
+		compare: cmp.Compare,
+	}
+}
+
+func (kv *UnrolledMap[K, V]) Length() int {
+	return kv.length
+}
+
+// skip to the block that might contain the key, the path contains the
+// rightmost block of each level which first key is less or equal to key.
+// The head is returned if key is less than any key of the map.
+func (kv *UnrolledMap[K, V]) skip(key K, path *[L]*ublock[K, V]) *ublock[K, V] {
+	node := kv.head
+	for lev := L - 1; lev >= 0; lev-- {
+		for next := node.fingers[lev]; next != nil && kv.compare(next.keys[0], key) <= 0; next = node.fingers[lev] {
+			node = next
+		}
+		path[lev] = node
+	}
+
+	return node
+}
+
+func (kv *UnrolledMap[K, V]) search(block *ublock[K, V], key K) (int, bool) {
+	pos := sort.Search(len(block.keys), func(i int) bool {
+		return kv.compare(block.keys[i], key) >= 0
+	})
+
+	return pos, pos < len(block.keys) && kv.compare(block.keys[pos], key) == 0
+}
+
+// Get value of the key
+func (kv *UnrolledMap[K, V]) Get(key K) (V, bool) {
+	var path [L]*ublock[K, V]
+
+	block := kv.skip(key, &path)
+	if pos, has := kv.search(block, key); has {
+		return block.values[pos], true
+	}
+
+	return *new(V), false
+}
+
+// Put key-value pair into map, return true if key is new
+func (kv *UnrolledMap[K, V]) Put(key K, val V) bool {
+	var path [L]*ublock[K, V]
+
+	block := kv.skip(key, &path)
+	if block == kv.head {
+		// key is less than any key, it is prepended to the first block
+		block = kv.head.fingers[0]
+		if block == nil {
+			kv.link(&path, &ublock[K, V]{
+				keys:   append(make([]K, 0, kv.size), key),
+				values: append(make([]V, 0, kv.size), val),
+			})
+			kv.length++
+			return true
+		}
+	}
+
+	pos, has := kv.search(block, key)
+	if has {
+		block.values[pos] = val
+		return false
+	}
+
+	if len(block.keys) == kv.size {
+		block = kv.split(&path, block, key)
+		pos, _ = kv.search(block, key)
+	}
+
+	block.keys = insertAt(block.keys, pos, key)
+	block.values = insertAt(block.values, pos, val)
+	kv.length++
+
+	return true
+}
+
+// split full block in halves, returns the half where key belongs to
+func (kv *UnrolledMap[K, V]) split(path *[L]*ublock[K, V], block *ublock[K, V], key K) *ublock[K, V] {
+	half := len(block.keys) / 2
+
+	tail := &ublock[K, V]{
+		keys:   append(make([]K, 0, kv.size), block.keys[half:]...),
+		values: append(make([]V, 0, kv.size), block.values[half:]...),
+	}
+
+	clear(block.keys[half:])
+	clear(block.values[half:])
+	block.keys = block.keys[:half]
+	block.values = block.values[:half]
+
+	// path points to the block or to its predecessors, the tail is linked
+	// right after the block
+	for lev := 0; lev < len(block.fingers); lev++ {
+		path[lev] = block
+	}
+	kv.link(path, tail)
+
+	if kv.compare(tail.keys[0], key) <= 0 {
+		return tail
+	}
+
+	return block
+}
+
+// link new block after the path
+func (kv *UnrolledMap[K, V]) link(path *[L]*ublock[K, V], block *ublock[K, V]) {
+	// See: https://golang.org/src/math/rand/rand.go#L150
+	p := float64(kv.random.Int63()) / (1 << 63)
+
+	level := 0
+	for level < L && p < probabilityTable[level] {
+		level++
+	}
+
+	block.fingers = make([]*ublock[K, V], level)
+	for lev := 0; lev < level; lev++ {
+		block.fingers[lev] = path[lev].fingers[lev]
+		path[lev].fingers[lev] = block
+	}
+}
+
+// Cut key from the map, returns the value and true if key is removed
+func (kv *UnrolledMap[K, V]) Cut(key K) (V, bool) {
+	var path [L]*ublock[K, V]
+
+	block := kv.skip(key, &path)
+	pos, has := kv.search(block, key)
+	if !has {
+		return *new(V), false
+	}
+
+	if len(block.keys) == 1 {
+		kv.unlink(block)
+	}
+
+	val := block.values[pos]
+	block.keys = deleteAt(block.keys, pos)
+	block.values = deleteAt(block.values, pos)
+	kv.length--
+
+	return val, true
+}
+
+// unlink block from the list, the block shall not be empty
+func (kv *UnrolledMap[K, V]) unlink(block *ublock[K, V]) {
+	node := kv.head
+	for lev := L - 1; lev >= 0; lev-- {
+		for next := node.fingers[lev]; next != nil && kv.compare(next.keys[0], block.keys[0]) < 0; next = node.fingers[lev] {
+			node = next
+		}
+
+		if node.fingers[lev] == block {
+			node.fingers[lev] = block.fingers[lev]
+		}
+	}
+}
+
+// All pairs of the map in order
+func (kv *UnrolledMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for block := kv.head.fingers[0]; block != nil; block = block.fingers[0] {
+			for i, key := range block.keys {
+				if !yield(key, block.values[i]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func insertAt[T any](seq []T, i int, x T) []T {
+	seq = append(seq, x)
+	copy(seq[i+1:], seq[i:])
+	seq[i] = x
+	return seq
+}
+
+func deleteAt[T any](seq []T, i int) []T {
+	copy(seq[i:], seq[i+1:])
+	clear(seq[len(seq)-1:])
+	return seq[:len(seq)-1]
+}
@@ -0,0 +1,64 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestUnrolledMap(t *testing.T) {
+	kv := skiplist.NewUnrolledMap[int, int](8)
+	ref := map[int]int{}
+
+	for i := 0; i < 10000; i++ {
+		key := rand.Intn(2000)
+		switch rand.Intn(3) {
+		case 0:
+			_, had := ref[key]
+			delete(ref, key)
+			_, has := kv.Cut(key)
+			it.Then(t).Should(it.Equal(has, had))
+		default:
+			_, had := ref[key]
+			ref[key] = i
+			it.Then(t).Should(it.Equal(kv.Put(key, i), !had))
+		}
+	}
+
+	it.Then(t).Should(it.Equal(kv.Length(), len(ref)))
+
+	for key, val := range ref {
+		v, has := kv.Get(key)
+		it.Then(t).Should(it.True(has), it.Equal(v, val))
+	}
+
+	prev, n := -1, 0
+	for key, val := range kv.All() {
+		it.Then(t).Should(
+			it.Less(prev, key),
+			it.Equal(val, ref[key]),
+		)
+		prev = key
+		n++
+	}
+	it.Then(t).Should(it.Equal(n, len(ref)))
+
+	for key := range ref {
+		kv.Cut(key)
+	}
+	_, has := kv.Get(1)
+	it.Then(t).Should(
+		it.Equal(kv.Length(), 0),
+		it.True(!has),
+	)
+}
@@ -0,0 +1,155 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"sort"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// VersionedMap is multi-version map, each write is recorded under a new
+// version so that the map is read as of any past version (point-in-time).
+// Versions are monotonically increasing, the first write gets version 1.
+type VersionedMap[K any, V any] struct {
+	kv      *Map[K, []revision[V]]
+	version uint64
+}
+
+// revision of the value, removed flag denotes a tombstone
+type revision[V any] struct {
+	version uint64
+	value   V
+	removed bool
+}
+
+// NewVersionedMap creates instance of multi-version map
+func NewVersionedMap[K Key, V any]() *VersionedMap[K, V] {
+	return NewVersionedMapWith[K, V](ord.Type[K]{})
+}
+
+// NewVersionedMapWith creates instance of multi-version map, keys are
+// ordered by the given type class
+func NewVersionedMapWith[K any, V any](cmp ord.Ord[K]) *VersionedMap[K, V] {
+	return &VersionedMap[K, V]{kv: NewMapWith[K, []revision[V]](cmp)}
+}
+
+// Version returns the latest version of the map
+func (kv *VersionedMap[K, V]) Version() uint64 {
+	return kv.version
+}
+
+// Length returns number of keys, including keys removed at the latest version
+// but still visible in the history.
+func (kv *VersionedMap[K, V]) Length() int {
+	return kv.kv.Length()
+}
+
+// Put value of the key, returns version of the write
+func (kv *VersionedMap[K, V]) Put(key K, val V) uint64 {
+	return kv.write(key, revision[V]{value: val})
+}
+
+// Cut key from the map, the history of the key remains readable.
+// Returns version of the write, or the current version if key has no
+// live value.
+func (kv *VersionedMap[K, V]) Cut(key K) uint64 {
+	if _, has := kv.Get(key); !has {
+		return kv.version
+	}
+
+	return kv.write(key, revision[V]{removed: true})
+}
+
+func (kv *VersionedMap[K, V]) write(key K, rev revision[V]) uint64 {
+	kv.version++
+	rev.version = kv.version
+
+	_, el := kv.kv.getOrInsert(key, nil)
+	el.Value = append(el.Value, rev)
+
+	return kv.version
+}
+
+// Get the latest value of the key
+func (kv *VersionedMap[K, V]) Get(key K) (V, bool) {
+	return kv.GetAt(key, kv.version)
+}
+
+// GetAt returns value of the key as of the version
+func (kv *VersionedMap[K, V]) GetAt(key K, version uint64) (V, bool) {
+	revs, has := kv.kv.Get(key)
+	if !has {
+		return *new(V), false
+	}
+
+	return lookupAt(revs, version)
+}
+
+// lookup the latest revision that is not newer than the version
+func lookupAt[V any](revs []revision[V], version uint64) (V, bool) {
+	i := sort.Search(len(revs), func(i int) bool { return revs[i].version > version })
+	if i == 0 || revs[i-1].removed {
+		return *new(V), false
+	}
+
+	return revs[i-1].value, true
+}
+
+// AllAt iterates over pairs of the map as of the version in key order
+//
+//	for k, v := range kv.AllAt(version) { /* ... */ }
+func (kv *VersionedMap[K, V]) AllAt(version uint64) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := kv.kv.Values(); e != nil; e = e.Next() {
+			if val, has := lookupAt(e.Value, version); has {
+				if !yield(e.Key, val) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// All pairs of the latest version in key order
+//
+//	for k, v := range kv.All() { /* ... */ }
+func (kv *VersionedMap[K, V]) All() iter.Seq2[K, V] {
+	return kv.AllAt(kv.version)
+}
+
+// Compact drops versions older than the given one. Reads as of the version
+// and later are not affected, reads of earlier versions are undefined after
+// compaction. Keys removed before the version are dropped entirely.
+func (kv *VersionedMap[K, V]) Compact(before uint64) {
+	dead := []K{}
+
+	for e := kv.kv.Values(); e != nil; e = e.Next() {
+		revs := e.Value
+
+		// the latest revision older than the version is retained,
+		// it defines value of the key at the version
+		i := sort.Search(len(revs), func(i int) bool { return revs[i].version > before })
+		if i > 1 {
+			revs = append(revs[:0:0], revs[i-1:]...)
+		}
+
+		if len(revs) == 1 && revs[0].removed && revs[0].version <= before {
+			dead = append(dead, e.Key)
+			continue
+		}
+
+		e.Value = revs
+	}
+
+	for _, key := range dead {
+		kv.kv.Cut(key)
+	}
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestVersionedMap(t *testing.T) {
+	kv := skiplist.NewVersionedMap[string, int]()
+
+	v1 := kv.Put("a", 1)
+	v2 := kv.Put("b", 2)
+	v3 := kv.Put("a", 3)
+	v4 := kv.Cut("b")
+	v5 := kv.Put("c", 5)
+
+	t.Run("GetAt", func(t *testing.T) {
+		a1, _ := kv.GetAt("a", v1)
+		a2, _ := kv.GetAt("a", v2)
+		a3, _ := kv.GetAt("a", v5)
+		b2, _ := kv.GetAt("b", v3)
+		_, hasB := kv.GetAt("b", v4)
+		_, hasC := kv.GetAt("c", v4)
+		_, hasA := kv.GetAt("a", 0)
+
+		it.Then(t).Should(
+			it.Equal(kv.Version(), v5),
+			it.Equal(a1, 1),
+			it.Equal(a2, 1),
+			it.Equal(a3, 3),
+			it.Equal(b2, 2),
+		).ShouldNot(
+			it.True(hasB),
+			it.True(hasC),
+			it.True(hasA),
+		)
+	})
+
+	t.Run("AllAt", func(t *testing.T) {
+		keys, vals := []string{}, []int{}
+		for k, v := range kv.AllAt(v3) {
+			keys = append(keys, k)
+			vals = append(vals, v)
+		}
+
+		latest := []string{}
+		for k := range kv.All() {
+			latest = append(latest, k)
+		}
+
+		it.Then(t).Should(
+			it.Seq(keys).Equal("a", "b"),
+			it.Seq(vals).Equal(3, 2),
+			it.Seq(latest).Equal("a", "c"),
+		)
+	})
+
+	t.Run("CutMissing", func(t *testing.T) {
+		kv := skiplist.NewVersionedMap[string, int]()
+		kv.Put("a", 1)
+		kv.Cut("a")
+
+		it.Then(t).Should(
+			it.Equal(kv.Cut("x"), kv.Version()),
+			it.Equal(kv.Cut("a"), kv.Version()),
+			it.Equal(kv.Version(), uint64(2)),
+			it.Equal(kv.Length(), 1),
+		)
+	})
+
+	t.Run("Compact", func(t *testing.T) {
+		kv.Compact(v4)
+
+		a, hasA := kv.GetAt("a", v4)
+		c, hasC := kv.Get("c")
+		_, hasB := kv.GetAt("b", v2)
+
+		it.Then(t).Should(
+			it.Equal(kv.Length(), 2),
+			it.Equal(a, 3),
+			it.True(hasA),
+			it.Equal(c, 5),
+			it.True(hasC),
+		).ShouldNot(
+			it.True(hasB),
+		)
+	})
+}
@@ -0,0 +1,257 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import "errors"
+
+// ErrOutOfRange is the panic value of writes to a view with key outside
+// of the view's interval
+var ErrOutOfRange = errors.New("skiplist: key is out of range of the view")
+
+// SubMap is a live view of the map restricted to key interval [from, to).
+// The view does not copy pairs, all operations are applied to the parent map.
+type SubMap[K any, V any] struct {
+	kv       *Map[K, V]
+	from, to K
+}
+
+// SubMap returns a view of the map restricted to key interval [from, to)
+func (kv *Map[K, V]) SubMap(from, to K) *SubMap[K, V] {
+	return &SubMap[K, V]{kv: kv, from: from, to: to}
+}
+
+// Check if key belongs to the view
+func (sub *SubMap[K, V]) Contains(key K) bool {
+	return sub.kv.compare(sub.from, key) <= 0 && sub.kv.compare(key, sub.to) < 0
+}
+
+// Number of pairs in the view, O(log n)
+func (sub *SubMap[K, V]) Length() int {
+	return sub.kv.CountRange(sub.from, sub.to)
+}
+
+// Put pair to the parent map, return true if pair is new.
+// It panics with ErrOutOfRange if key is outside of the view.
+func (sub *SubMap[K, V]) Put(key K, val V) bool {
+	if !sub.Contains(key) {
+		panic(ErrOutOfRange)
+	}
+
+	return sub.kv.Put(key, val)
+}
+
+// Get value from the parent map
+func (sub *SubMap[K, V]) Get(key K) (V, bool) {
+	if !sub.Contains(key) {
+		return *new(V), false
+	}
+
+	return sub.kv.Get(key)
+}
+
+// Cut pair from the parent map, returns removed value and true if key existed
+func (sub *SubMap[K, V]) Cut(key K) (V, bool) {
+	if !sub.Contains(key) {
+		return *new(V), false
+	}
+
+	return sub.kv.Cut(key)
+}
+
+// At returns pair at position i of the view, pairs are indexed from 0
+func (sub *SubMap[K, V]) At(i int) *Pair[K, V] {
+	if i < 0 {
+		return nil
+	}
+
+	rank, _ := sub.kv.RankOf(sub.from)
+	el := sub.kv.At(rank + i)
+	if el == nil || !sub.Contains(el.Key) {
+		return nil
+	}
+
+	return el
+}
+
+// All pairs of the view
+func (sub *SubMap[K, V]) Values() *Pair[K, V] {
+	return sub.Successor(sub.from)
+}
+
+// Successor pairs of the view
+func (sub *SubMap[K, V]) Successor(key K) *Pair[K, V] {
+	if sub.kv.compare(key, sub.from) < 0 {
+		key = sub.from
+	}
+
+	el := sub.kv.Successor(key)
+	if el == nil || sub.kv.compare(el.Key, sub.to) >= 0 {
+		return nil
+	}
+
+	return el
+}
+
+// Iterate over pairs of the view
+//
+//	seq := skiplist.ForSubMap(sub, sub.Successor(key))
+//	for has := seq != nil; has; has = seq.Next() {
+//		seq.Key()
+//	}
+func ForSubMap[K any, V any](sub *SubMap[K, V], el *Pair[K, V]) PairSeq[K, V] {
+	if el == nil {
+		return nil
+	}
+
+	return &forSubMap[K, V]{forMap: forMap[K, V]{kv: sub.kv, el: el}, sub: sub}
+}
+
+type forSubMap[K any, V any] struct {
+	forMap[K, V]
+	sub *SubMap[K, V]
+}
+
+func (it *forSubMap[K, V]) Next() bool {
+	return it.forMap.Next() && it.bounded()
+}
+
+func (it *forSubMap[K, V]) Seek(key K) bool {
+	return it.forMap.Seek(key) && it.bounded()
+}
+
+func (it *forSubMap[K, V]) bounded() bool {
+	if it.sub.kv.compare(it.el.Key, it.sub.to) >= 0 {
+		it.el = nil
+		return false
+	}
+
+	return true
+}
+
+// --------------------------------------------------------------------------------------
+
+// SubSet is a live view of the set restricted to key interval [from, to).
+// The view does not copy elements, all operations are applied to the parent set.
+type SubSet[K any] struct {
+	set      *Set[K]
+	from, to K
+}
+
+// SubSet returns a view of the set restricted to key interval [from, to)
+func (set *Set[K]) SubSet(from, to K) *SubSet[K] {
+	return &SubSet[K]{set: set, from: from, to: to}
+}
+
+// Check if key belongs to the view
+func (sub *SubSet[K]) Contains(key K) bool {
+	return sub.set.compare(sub.from, key) <= 0 && sub.set.compare(key, sub.to) < 0
+}
+
+// Number of elements in the view, O(log n)
+func (sub *SubSet[K]) Length() int {
+	return sub.set.CountRange(sub.from, sub.to)
+}
+
+// Add element to the parent set, return true if element is new.
+// It panics with ErrOutOfRange if key is outside of the view.
+func (sub *SubSet[K]) Add(key K) bool {
+	if !sub.Contains(key) {
+		panic(ErrOutOfRange)
+	}
+
+	return sub.set.Add(key)
+}
+
+// Check is element exists in the view
+func (sub *SubSet[K]) Has(key K) bool {
+	if !sub.Contains(key) {
+		return false
+	}
+
+	return sub.set.Has(key)
+}
+
+// Cut element from the parent set, returns true if element is removed
+func (sub *SubSet[K]) Cut(key K) bool {
+	if !sub.Contains(key) {
+		return false
+	}
+
+	return sub.set.Cut(key)
+}
+
+// At returns element at position i of the view, elements are indexed from 0
+func (sub *SubSet[K]) At(i int) *Element[K] {
+	if i < 0 {
+		return nil
+	}
+
+	rank, _ := sub.set.RankOf(sub.from)
+	el := sub.set.At(rank + i)
+	if el == nil || !sub.Contains(el.Key) {
+		return nil
+	}
+
+	return el
+}
+
+// All elements of the view
+func (sub *SubSet[K]) Values() *Element[K] {
+	return sub.Successor(sub.from)
+}
+
+// Successor elements of the view
+func (sub *SubSet[K]) Successor(key K) *Element[K] {
+	if sub.set.compare(key, sub.from) < 0 {
+		key = sub.from
+	}
+
+	el := sub.set.Successor(key)
+	if el == nil || sub.set.compare(el.Key, sub.to) >= 0 {
+		return nil
+	}
+
+	return el
+}
+
+// Iterate over elements of the view
+//
+//	seq := skiplist.ForSubSet(sub, sub.Successor(key))
+//	for has := seq != nil; has; has = seq.Next() {
+//		seq.Value()
+//	}
+func ForSubSet[K any](sub *SubSet[K], el *Element[K]) Seq[K] {
+	if el == nil {
+		return nil
+	}
+
+	return &forSubSet[K]{forSet: forSet[K]{set: sub.set, el: el}, sub: sub}
+}
+
+type forSubSet[K any] struct {
+	forSet[K]
+	sub *SubSet[K]
+}
+
+func (it *forSubSet[K]) Next() bool {
+	return it.forSet.Next() && it.bounded()
+}
+
+func (it *forSubSet[K]) Seek(key K) bool {
+	return it.forSet.Seek(key) && it.bounded()
+}
+
+func (it *forSubSet[K]) bounded() bool {
+	if it.sub.set.compare(it.el.Key, it.sub.to) >= 0 {
+		it.el = nil
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,150 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestSubMap(t *testing.T) {
+	kv := skiplist.NewMap[int, int]()
+	for i := 0; i < 100; i++ {
+		kv.Put(i, i)
+	}
+
+	sub := kv.SubMap(10, 20)
+
+	t.Run("Length", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Equal(sub.Length(), 10),
+		)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		val, has := sub.Get(15)
+		_, none := sub.Get(20)
+		it.Then(t).Should(
+			it.True(has),
+			it.Equal(val, 15),
+			it.True(!none),
+		)
+	})
+
+	t.Run("At", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Equal(sub.At(0).Key, 10),
+			it.Equal(sub.At(9).Key, 19),
+			it.True(sub.At(10) == nil),
+			it.True(sub.At(-1) == nil),
+		)
+	})
+
+	t.Run("Seq", func(t *testing.T) {
+		i := 10
+		seq := skiplist.ForSubMap(sub, sub.Values())
+		for has := seq != nil; has; has = seq.Next() {
+			it.Then(t).Should(
+				it.Equal(seq.Key(), i),
+				it.Equal(seq.Value(), i),
+			)
+			i++
+		}
+		it.Then(t).Should(
+			it.Equal(i, 20),
+			it.Equal(sub.Successor(5).Key, 10),
+			it.True(sub.Successor(25) == nil),
+		)
+	})
+
+	t.Run("Put", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Fail(func() { sub.Put(25, 25) }).Contain(skiplist.ErrOutOfRange.Error()),
+		)
+
+		kv.Cut(15)
+		isNew := sub.Put(15, 15)
+		it.Then(t).Should(
+			it.True(isNew),
+			it.Equal(kv.Length(), 100),
+		)
+	})
+
+	t.Run("Cut", func(t *testing.T) {
+		_, cut := sub.Cut(25)
+		it.Then(t).ShouldNot(
+			it.True(cut),
+		)
+
+		_, cut = sub.Cut(15)
+		it.Then(t).Should(
+			it.True(cut),
+			it.Equal(sub.Length(), 9),
+			it.Equal(kv.Length(), 99),
+		)
+	})
+}
+
+func TestSubSet(t *testing.T) {
+	set := skiplist.NewSet[int]()
+	for i := 0; i < 100; i++ {
+		set.Add(i)
+	}
+
+	sub := set.SubSet(10, 20)
+
+	t.Run("Length", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Equal(sub.Length(), 10),
+		)
+	})
+
+	t.Run("Has", func(t *testing.T) {
+		has := sub.Has(15)
+		none := sub.Has(20)
+		it.Then(t).Should(
+			it.True(has),
+		).ShouldNot(
+			it.True(none),
+		)
+	})
+
+	t.Run("At", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Equal(sub.At(0).Key, 10),
+			it.Equal(sub.At(9).Key, 19),
+			it.True(sub.At(10) == nil),
+		)
+	})
+
+	t.Run("Seq", func(t *testing.T) {
+		i := 10
+		seq := skiplist.ForSubSet(sub, sub.Values())
+		for has := seq != nil; has; has = seq.Next() {
+			it.Then(t).Should(
+				it.Equal(seq.Value(), i),
+			)
+			i++
+		}
+		it.Then(t).Should(
+			it.Equal(i, 20),
+		)
+	})
+
+	t.Run("AddCut", func(t *testing.T) {
+		cut := sub.Cut(15)
+		it.Then(t).Should(
+			it.True(cut),
+			it.Equal(sub.Length(), 9),
+			it.Fail(func() { sub.Add(120) }).Contain(skiplist.ErrOutOfRange.Error()),
+		)
+	})
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// EventType of mutation observed by Watch
+type EventType int
+
+const (
+	// EventInsert is put of new key
+	EventInsert EventType = iota
+	// EventUpdate is put of existing key
+	EventUpdate
+	// EventDelete is removal of the key
+	EventDelete
+)
+
+// Event of mutation observed by Watch, Old is the replaced (EventUpdate)
+// or removed (EventDelete) value.
+type Event[K any, V any] struct {
+	Type  EventType
+	Key   K
+	Value V
+	Old   V
+}
+
+// Watch delivers ordered events of mutations of keys within the interval
+// [from, to). The channel is closed when context is cancelled. Events are
+// buffered, slow consumer never blocks writers of the map.
+//
+// The map is not thread safe, Watch shall be called by the writer.
+//
+//	for e := range kv.Watch(ctx, from, to) { /* ... */ }
+func (kv *Map[K, V]) Watch(ctx context.Context, from, to K) <-chan Event[K, V] {
+	if kv.watch == nil {
+		kv.watch = &watch[K, V]{compare: kv.compare}
+	}
+
+	w := &watcher[K, V]{
+		from: from,
+		to:   to,
+		wake: make(chan struct{}, 1),
+	}
+	kv.watch.seq = append(kv.watch.seq, w)
+
+	ch := make(chan Event[K, V])
+	go w.pump(ctx, ch)
+
+	return ch
+}
+
+// watchers of the map
+type watch[K any, V any] struct {
+	compare func(K, K) int
+	seq     []*watcher[K, V]
+}
+
+func (kv *watch[K, V]) put(key K, old, val V, updated bool) {
+	evt := Event[K, V]{Type: EventInsert, Key: key, Value: val}
+	if updated {
+		evt.Type, evt.Old = EventUpdate, old
+	}
+
+	kv.notify(evt)
+}
+
+func (kv *watch[K, V]) cut(key K, val V) {
+	kv.notify(Event[K, V]{Type: EventDelete, Key: key, Old: val})
+}
+
+// notify watchers of the key, closed watchers are released
+func (kv *watch[K, V]) notify(evt Event[K, V]) {
+	seq := kv.seq[:0]
+	for _, w := range kv.seq {
+		if w.closed.Load() {
+			continue
+		}
+		seq = append(seq, w)
+
+		if kv.compare(w.from, evt.Key) <= 0 && kv.compare(evt.Key, w.to) < 0 {
+			w.push(evt)
+		}
+	}
+
+	clear(kv.seq[len(seq):])
+	kv.seq = seq
+}
+
+// watcher of key range, events are queued by writer and delivered by pump
+type watcher[K any, V any] struct {
+	from, to K
+	mu       sync.Mutex
+	queue    []Event[K, V]
+	wake     chan struct{}
+	closed   atomic.Bool
+}
+
+func (w *watcher[K, V]) push(evt Event[K, V]) {
+	w.mu.Lock()
+	w.queue = append(w.queue, evt)
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher[K, V]) pump(ctx context.Context, ch chan<- Event[K, V]) {
+	defer close(ch)
+	defer w.closed.Store(true)
+
+	for {
+		w.mu.Lock()
+		batch := w.queue
+		w.queue = nil
+		w.mu.Unlock()
+
+		for _, evt := range batch {
+			select {
+			case ch <- evt:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if len(batch) == 0 {
+			select {
+			case <-w.wake:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestMapWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	kv := skiplist.NewMap[int, string]()
+	ch := kv.Watch(ctx, 10, 20)
+
+	kv.Put(5, "a")
+	kv.Put(10, "b")
+	kv.Put(15, "c")
+	kv.Put(10, "d")
+	kv.Put(20, "e")
+	kv.Cut(15)
+	kv.CutRange(0, 100)
+
+	expect := []skiplist.Event[int, string]{
+		{Type: skiplist.EventInsert, Key: 10, Value: "b"},
+		{Type: skiplist.EventInsert, Key: 15, Value: "c"},
+		{Type: skiplist.EventUpdate, Key: 10, Value: "d", Old: "b"},
+		{Type: skiplist.EventDelete, Key: 15, Old: "c"},
+		{Type: skiplist.EventDelete, Key: 10, Old: "d"},
+	}
+
+	for _, e := range expect {
+		it.Then(t).Should(it.Equal(<-ch, e))
+	}
+
+	cancel()
+	for range ch {
+	}
+
+	// writes after cancellation release the watcher
+	kv.Put(11, "f")
+}
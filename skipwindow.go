@@ -0,0 +1,68 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"iter"
+	"math"
+)
+
+// Window is time series of values keyed by timestamp (e.g. Unix nano).
+// Trim drops the head of the series in O(log n), which makes the window
+// suitable for buffering of metrics.
+type Window[V any] struct {
+	kv *Map[int64, V]
+}
+
+// NewWindow creates instance of time series window
+func NewWindow[V any](opts ...MapConfig[int64, V]) *Window[V] {
+	return &Window[V]{kv: NewMap(opts...)}
+}
+
+// Length returns number of values in the window
+func (w *Window[V]) Length() int {
+	return w.kv.Length()
+}
+
+// Append value at timestamp, the value at existing timestamp is replaced.
+// Returns true if timestamp is new.
+func (w *Window[V]) Append(ts int64, val V) bool {
+	return w.kv.Put(ts, val)
+}
+
+// Get value at timestamp
+func (w *Window[V]) Get(ts int64) (V, bool) {
+	return w.kv.Get(ts)
+}
+
+// RangeBetween yields values with timestamp in the interval [t0, t1), the
+// interval follows order of the window (t0 > t1 for descending window).
+//
+//	for ts, v := range w.RangeBetween(t0, t1) { /* ... */ }
+func (w *Window[V]) RangeBetween(t0, t1 int64) iter.Seq2[int64, V] {
+	return w.kv.Range(t0, t1)
+}
+
+// Trim drops values older than the timestamp, returns number of dropped values
+func (w *Window[V]) Trim(olderThan int64) int {
+	return w.kv.CutRange(math.MinInt64, olderThan)
+}
+
+// All values in order of timestamp
+//
+//	for ts, v := range w.All() { /* ... */ }
+func (w *Window[V]) All() iter.Seq2[int64, V] {
+	return func(yield func(int64, V) bool) {
+		for e := w.kv.Values(); e != nil; e = e.Next() {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
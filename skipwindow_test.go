@@ -0,0 +1,63 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestWindow(t *testing.T) {
+	w := skiplist.NewWindow[float64]()
+	for ts := int64(0); ts < 100; ts += 10 {
+		w.Append(ts, float64(ts)/10)
+	}
+
+	it.Then(t).Should(
+		it.Equal(w.Length(), 10),
+		it.Equal(w.Append(50, 0.5), false),
+		it.Equal(w.Append(55, 5.5), true),
+	)
+
+	val, has := w.Get(50)
+	it.Then(t).Should(it.True(has), it.Equal(val, 0.5))
+
+	seq := []int64{}
+	for ts := range w.RangeBetween(35, 70) {
+		seq = append(seq, ts)
+	}
+	it.Then(t).Should(it.Seq(seq).Equal(40, 50, 55, 60))
+
+	it.Then(t).Should(
+		it.Equal(w.Trim(50), 5),
+		it.Equal(w.Trim(50), 0),
+		it.Equal(w.Length(), 6),
+	)
+
+	seq = seq[:0]
+	for ts := range w.All() {
+		seq = append(seq, ts)
+	}
+	it.Then(t).Should(it.Seq(seq).Equal(50, 55, 60, 70, 80, 90))
+}
+
+func TestWindowDescending(t *testing.T) {
+	w := skiplist.NewWindow(skiplist.MapWithDescending[int64, int]())
+	for ts := int64(0); ts < 100; ts += 10 {
+		w.Append(ts, int(ts))
+	}
+
+	seq := []int64{}
+	for ts := range w.RangeBetween(70, 35) {
+		seq = append(seq, ts)
+	}
+	it.Then(t).Should(it.Seq(seq).Equal(70, 60, 50, 40))
+}
@@ -0,0 +1,171 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"errors"
+	"iter"
+	"math"
+
+	"github.com/fogfish/skiplist/ord"
+)
+
+// ZSet is sorted set of members ordered by score, members with equal score
+// are ordered by the member itself. The set is skip map keyed by
+// (score, member) with hash index of member scores. Updates, Rank and
+// range lookups takes O(log n), Score takes O(1).
+type ZSet[M comparable] struct {
+	kv    *Map[zkey[M], struct{}]
+	score map[M]float64
+}
+
+// ErrNaNScore is the panic value of ZSet updates with NaN score, NaN is not
+// ordered and corrupts the order of members
+var ErrNaNScore = errors.New("skiplist: score is NaN")
+
+// key of the set, the bound allows to seek position of the score
+// regardless of members
+type zkey[M any] struct {
+	score  float64
+	bound  int
+	member M
+}
+
+// NewZSet creates instance of sorted set
+func NewZSet[M Key]() *ZSet[M] {
+	return NewZSetWith[M](ord.Type[M]{})
+}
+
+// NewZSetWith creates instance of sorted set, members of equal score
+// are ordered by the given type class
+func NewZSetWith[M comparable](cmp ord.Ord[M]) *ZSet[M] {
+	compare := func(a, b zkey[M]) int {
+		switch {
+		case a.score < b.score:
+			return -1
+		case a.score > b.score:
+			return 1
+		case a.bound != b.bound:
+			return a.bound - b.bound
+		default:
+			return cmp.Compare(a.member, b.member)
+		}
+	}
+
+	return &ZSet[M]{
+		kv:    NewMapFunc[zkey[M], struct{}](compare),
+		score: make(map[M]float64),
+	}
+}
+
+// Length returns number of members
+func (set *ZSet[M]) Length() int {
+	return set.kv.Length()
+}
+
+// Add member with the score, the score is updated if member exists.
+// Returns true if member is new. It panics with ErrNaNScore if score is NaN.
+func (set *ZSet[M]) Add(member M, score float64) bool {
+	if math.IsNaN(score) {
+		panic(ErrNaNScore)
+	}
+
+	old, has := set.score[member]
+	if has {
+		if old == score {
+			return false
+		}
+		set.kv.Cut(zkey[M]{score: old, member: member})
+	}
+
+	set.kv.Put(zkey[M]{score: score, member: member}, struct{}{})
+	set.score[member] = score
+
+	return !has
+}
+
+// IncrBy increments score of the member by delta, returns new score.
+// The member is added with delta score if it does not exist. It panics with
+// ErrNaNScore if new score is NaN (e.g. sum of +Inf and -Inf).
+func (set *ZSet[M]) IncrBy(member M, delta float64) float64 {
+	score := set.score[member] + delta
+	set.Add(member, score)
+
+	return score
+}
+
+// Score of the member
+func (set *ZSet[M]) Score(member M) (float64, bool) {
+	score, has := set.score[member]
+	return score, has
+}
+
+// Cut member from the set, returns true if member is removed
+func (set *ZSet[M]) Cut(member M) bool {
+	score, has := set.score[member]
+	if !has {
+		return false
+	}
+
+	set.kv.Cut(zkey[M]{score: score, member: member})
+	delete(set.score, member)
+
+	return true
+}
+
+// Rank of the member, members are indexed from 0 in order of score
+func (set *ZSet[M]) Rank(member M) (int, bool) {
+	score, has := set.score[member]
+	if !has {
+		return 0, false
+	}
+
+	return set.kv.RankOf(zkey[M]{score: score, member: member})
+}
+
+// RangeByRank yields members with rank in the interval [from, to)
+//
+//	for member, score := range set.RangeByRank(0, 10) { /* ... */ }
+func (set *ZSet[M]) RangeByRank(from, to int) iter.Seq2[M, float64] {
+	return func(yield func(M, float64) bool) {
+		n := max(from, 0)
+		for e := set.kv.At(n); e != nil && n < to; e = e.Next() {
+			if !yield(e.Key.member, e.Key.score) {
+				return
+			}
+			n++
+		}
+	}
+}
+
+// RangeByScore yields members with score in the interval [lo, hi]
+//
+//	for member, score := range set.RangeByScore(lo, hi) { /* ... */ }
+func (set *ZSet[M]) RangeByScore(lo, hi float64) iter.Seq2[M, float64] {
+	return func(yield func(M, float64) bool) {
+		for e := set.kv.Successor(zkey[M]{score: lo, bound: -1}); e != nil && e.Key.score <= hi; e = e.Next() {
+			if !yield(e.Key.member, e.Key.score) {
+				return
+			}
+		}
+	}
+}
+
+// All members in order of score
+//
+//	for member, score := range set.All() { /* ... */ }
+func (set *ZSet[M]) All() iter.Seq2[M, float64] {
+	return func(yield func(M, float64) bool) {
+		for e := set.kv.Values(); e != nil; e = e.Next() {
+			if !yield(e.Key.member, e.Key.score) {
+				return
+			}
+		}
+	}
+}
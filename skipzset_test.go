@@ -0,0 +1,87 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestZSet(t *testing.T) {
+	set := skiplist.NewZSet[int]()
+
+	it.Then(t).Should(
+		it.Equal(set.Add(-1, 2.0), true),
+		it.Equal(set.Add(10, 1.0), true),
+		it.Equal(set.Add(20, 2.0), true),
+		it.Equal(set.Add(30, 3.0), true),
+		it.Equal(set.Add(10, 5.0), false),
+		it.Equal(set.Length(), 4),
+	)
+
+	members := func(seq func(func(int, float64) bool)) []int {
+		out := []int{}
+		for m := range seq {
+			out = append(out, m)
+		}
+		return out
+	}
+
+	it.Then(t).Should(
+		it.Seq(members(set.All())).Equal(-1, 20, 30, 10),
+		it.Seq(members(set.RangeByScore(2.0, 3.0))).Equal(-1, 20, 30),
+		it.Seq(members(set.RangeByScore(2.5, 4.0))).Equal(30),
+		it.Seq(members(set.RangeByRank(1, 3))).Equal(20, 30),
+		it.Seq(members(set.RangeByRank(3, 10))).Equal(10),
+	)
+
+	rank, has := set.Rank(30)
+	it.Then(t).Should(it.True(has), it.Equal(rank, 2))
+
+	it.Then(t).Should(
+		it.Equal(set.IncrBy(30, -2.5), 0.5),
+		it.Equal(set.IncrBy(40, 1.5), 1.5),
+		it.Seq(members(set.All())).Equal(30, 40, -1, 20, 10),
+	)
+
+	score, has := set.Score(40)
+	it.Then(t).Should(it.True(has), it.Equal(score, 1.5))
+
+	it.Then(t).Should(
+		it.Equal(set.Cut(-1), true),
+		it.Equal(set.Cut(-1), false),
+		it.Equal(set.Length(), 4),
+		it.Seq(members(set.All())).Equal(30, 40, 20, 10),
+	)
+
+	_, has = set.Rank(-1)
+	it.Then(t).Should(it.True(!has))
+}
+
+func TestZSetNaN(t *testing.T) {
+	set := skiplist.NewZSet[int]()
+	set.Add(1, 1.0)
+	set.Add(2, math.Inf(1))
+
+	it.Then(t).Should(
+		it.Fail(func() { set.Add(3, math.NaN()) }).Contain(skiplist.ErrNaNScore.Error()),
+		it.Fail(func() { set.IncrBy(2, math.Inf(-1)) }).Contain(skiplist.ErrNaNScore.Error()),
+		it.Equal(set.Length(), 2),
+	)
+
+	score, _ := set.Score(2)
+	rank, _ := set.Rank(2)
+	it.Then(t).Should(
+		it.Equal(score, math.Inf(1)),
+		it.Equal(rank, 1),
+	)
+}
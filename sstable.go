@@ -0,0 +1,140 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+)
+
+// Flush writes content of the map as sorted table (SSTable), the shape used
+// by LSM storages when memtable is flushed to disk. Pairs are packed into
+// blocks, each block is compressed with DEFLATE. Index of blocks and footer
+// completes the table:
+//
+//	block := deflate({ uvarint(len) key uvarint(len) value })
+//	index := { uvarint(len) first-key uvarint(offset) uvarint(size) uvarint(count) }
+//	footer := uint64(index offset) uint64(index size) "skl\x03"
+//
+// Keys and values follow the binary format (see WriteTo).
+func (kv *Map[K, V]) Flush(w io.Writer, opts ...FlushConfig) (int64, error) {
+	conf := flushConfig{blockSize: 4096, level: flate.DefaultCompression}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	var (
+		block bytes.Buffer
+		first []byte
+		raw   []byte
+		index []byte
+		count int
+		err   error
+	)
+
+	zw, err := flate.NewWriter(&block, conf.level)
+	if err != nil {
+		return 0, err
+	}
+
+	flush := func() error {
+		offset := cw.n + int64(bw.Buffered())
+
+		block.Reset()
+		zw.Reset(&block)
+		if _, err := zw.Write(raw); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		if _, err := bw.Write(block.Bytes()); err != nil {
+			return err
+		}
+
+		index = append(index, first...)
+		index = binary.AppendUvarint(index, uint64(offset))
+		index = binary.AppendUvarint(index, uint64(block.Len()))
+		index = binary.AppendUvarint(index, uint64(count))
+
+		raw, first, count = raw[:0], first[:0], 0
+		return nil
+	}
+
+	for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		at := len(raw)
+		if raw, err = appendBinaryField(raw, e.Key); err != nil {
+			return cw.n, err
+		}
+		if count == 0 {
+			first = append(first, raw[at:]...)
+		}
+		if raw, err = kv.appendValue(raw, e.Value); err != nil {
+			return cw.n, err
+		}
+
+		count++
+		if len(raw) >= conf.blockSize {
+			if err := flush(); err != nil {
+				return cw.n, err
+			}
+		}
+	}
+
+	if count > 0 {
+		if err := flush(); err != nil {
+			return cw.n, err
+		}
+	}
+
+	offset := cw.n + int64(bw.Buffered())
+	footer := binary.BigEndian.AppendUint64(nil, uint64(offset))
+	footer = binary.BigEndian.AppendUint64(footer, uint64(len(index)))
+	footer = append(footer, magicSSTable[:]...)
+
+	if _, err := bw.Write(index); err != nil {
+		return cw.n, err
+	}
+	if _, err := bw.Write(footer); err != nil {
+		return cw.n, err
+	}
+
+	err = bw.Flush()
+	return cw.n, err
+}
+
+var magicSSTable = [4]byte{'s', 'k', 'l', 3}
+
+type flushConfig struct {
+	blockSize int
+	level     int
+}
+
+// Configure Flush of sorted table
+type FlushConfig func(*flushConfig)
+
+// Configure size of uncompressed block, default 4KiB
+func FlushWithBlockSize(size int) FlushConfig {
+	return func(conf *flushConfig) {
+		conf.blockSize = size
+	}
+}
+
+// Configure compression level of blocks, see compress/flate
+func FlushWithCompression(level int) FlushConfig {
+	return func(conf *flushConfig) {
+		conf.level = level
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestFlush(t *testing.T) {
+	kv := skiplist.NewMap[string, string]()
+	for i := 0; i < 1000; i++ {
+		kv.Put(fmt.Sprintf("key-%04d", i), fmt.Sprintf("val-%d", i))
+	}
+
+	var buf bytes.Buffer
+	n, err := kv.Flush(&buf, skiplist.FlushWithBlockSize(512))
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(n, int64(buf.Len())),
+	)
+
+	table := buf.Bytes()
+	footer := table[len(table)-20:]
+	offset := binary.BigEndian.Uint64(footer[0:8])
+	size := binary.BigEndian.Uint64(footer[8:16])
+	it.Then(t).Should(
+		it.Equal(string(footer[16:]), "skl\x03"),
+		it.Equal(offset+size, uint64(len(table)-20)),
+	)
+
+	field := func(r *bytes.Reader) string {
+		n, _ := binary.ReadUvarint(r)
+		b := make([]byte, n)
+		io.ReadFull(r, b)
+		return string(b)
+	}
+
+	seq := kv.Values()
+	blocks := 0
+	index := bytes.NewReader(table[offset : offset+size])
+	for index.Len() > 0 {
+		first := field(index)
+		at, _ := binary.ReadUvarint(index)
+		sz, _ := binary.ReadUvarint(index)
+		count, _ := binary.ReadUvarint(index)
+
+		raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(table[at : at+sz])))
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(first, seq.Key),
+		)
+
+		block := bytes.NewReader(raw)
+		for i := uint64(0); i < count; i++ {
+			it.Then(t).Should(
+				it.Equal(field(block), seq.Key),
+				it.Equal(field(block), seq.Value),
+			)
+			seq = seq.Next()
+		}
+		it.Then(t).Should(it.Equal(block.Len(), 0))
+		blocks++
+	}
+
+	it.Then(t).Should(
+		it.True(seq == nil),
+		it.True(blocks > 1),
+	)
+
+	t.Run("Empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := skiplist.NewMap[string, string]().Flush(&buf)
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(buf.Len(), 20),
+		)
+	})
+}
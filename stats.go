@@ -0,0 +1,89 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+// Stats is a level distribution of skip list, it helps to verify
+// the effect of probability (block size) configuration on real data.
+type Stats struct {
+	// number of nodes
+	Length int
+
+	// number of nodes at each level, Levels[0] is equal to Length
+	Levels []int
+
+	// average rank (number of fingers) of nodes
+	AvgRank float64
+
+	// expected number of nodes at each level for the probability table
+	Expected []float64
+
+	// relative deviation of actual number of nodes from expected one at
+	// each level, 0 is a perfect match
+	Deviation []float64
+}
+
+// build stats from counters of nodes at each level
+func newStats(length int, levels [L]int, ptable [L]float64) Stats {
+	top := 0
+	for top < L && levels[top] > 0 {
+		top++
+	}
+
+	stats := Stats{
+		Length:    length,
+		Levels:    make([]int, top),
+		Expected:  make([]float64, top),
+		Deviation: make([]float64, top),
+	}
+
+	fingers := 0
+	for i := 0; i < top; i++ {
+		fingers += levels[i]
+		stats.Levels[i] = levels[i]
+		stats.Expected[i] = float64(length) * ptable[i]
+		stats.Deviation[i] = float64(levels[i])/stats.Expected[i] - 1
+	}
+
+	if length > 0 {
+		stats.AvgRank = float64(fingers) / float64(length)
+	}
+
+	return stats
+}
+
+// Stats of the map, it takes O(n)
+func (kv *Map[K, V]) Stats() Stats {
+	var levels [L]int
+
+	for e := kv.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		for i := range e.Fingers {
+			levels[i]++
+		}
+	}
+
+	return newStats(kv.length, levels, kv.ptable)
+}
+
+// Stats of the set, it takes O(n)
+func (set *Set[K]) Stats() Stats {
+	var levels [L]int
+
+	for e := set.head.Fingers[0]; e != nil; e = e.Fingers[0] {
+		for i := range e.Fingers {
+			levels[i]++
+		}
+	}
+
+	return newStats(set.length, levels, set.ptable)
+}
+
+// Stats of the map, it takes O(n)
+func (kv *HashMap[K, V]) Stats() Stats {
+	return kv.keys.Stats()
+}
@@ -0,0 +1,45 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestStats(t *testing.T) {
+	kv := skiplist.NewMap(skiplist.MapWithProbability[int, int](0.5))
+	for i := 0; i < 100000; i++ {
+		kv.Put(i, i)
+	}
+
+	stats := kv.Stats()
+	it.Then(t).Should(
+		it.Equal(stats.Length, 100000),
+		it.Equal(stats.Levels[0], 100000),
+		it.Equal(stats.Expected[1], 50000.0),
+		it.Equal(stats.Deviation[0], 0.0),
+		it.Less(stats.Deviation[1], 0.05),
+		it.Greater(stats.Deviation[1], -0.05),
+		it.Less(stats.AvgRank, 2.1),
+		it.Greater(stats.AvgRank, 1.9),
+		it.Equal(len(stats.Levels), kv.Level()+1),
+	)
+
+	t.Run("Empty", func(t *testing.T) {
+		stats := skiplist.NewSet[int]().Stats()
+		it.Then(t).Should(
+			it.Equal(stats.Length, 0),
+			it.Equal(len(stats.Levels), 0),
+			it.Equal(stats.AvgRank, 0.0),
+		)
+	})
+}
@@ -14,6 +14,11 @@
 // http://citeseerx.ist.psu.edu/viewdoc/summary?doi=10.1.1.17.524
 package skiplist
 
+import (
+	"hash/fnv"
+	"math/bits"
+)
+
 // L depth of fingers at each node.
 //
 // The value is estimated as math.Log10(float64(n)) / math.Log10(1/p)
@@ -23,7 +28,9 @@ const L = 22
 // The probability table is generated for L=22
 var probabilityTable [L]float64 = [L]float64{1, 0.36787944117144233, 0.1353352832366127, 0.04978706836786395, 0.018315638888734182, 0.006737946999085468, 0.002478752176666359, 0.0009118819655545165, 0.0003354626279025119, 0.0001234098040866796, 4.539992976248486e-05, 1.6701700790245666e-05, 6.1442123533282115e-06, 2.260329406981055e-06, 8.315287191035682e-07, 3.0590232050182594e-07, 1.1253517471925916e-07, 4.139937718785168e-08, 1.5229979744712636e-08, 5.60279643753727e-09, 2.0611536224385587e-09, 7.582560427911911e-10}
 
-// Constraint on key types supported by the data structures
+// Constraint on key types supported by the data structures.
+// Float keys are ordered by < operator, NaN keys corrupt the structure,
+// use ord.Float64Total (ord.Float32Total) if keys might be NaN.
 type Key interface {
 	~string |
 		~int | ~int8 | ~int16 | ~int32 | ~int64 |
@@ -32,7 +39,123 @@ type Key interface {
 }
 
 // Memory allocator
-type Allocator[K Key, T any] interface {
+type Allocator[K any, T any] interface {
 	Alloc(K) *T
 	Free(K)
 }
+
+// NodeAllocator is memory allocator that manages nodes by level and
+// reference. The allocator is aware of the level of node, it allocates
+// exactly level fingers (e.g. from slab of memory), and it is able to
+// release the node itself rather than the key.
+//
+// Containers use AllocNode and FreeNode if allocator implements the interface.
+type NodeAllocator[K any, T any] interface {
+	Allocator[K, T]
+
+	// AllocNode allocates node of the key with level fingers
+	AllocNode(key K, level int) *T
+
+	// FreeNode releases the node removed from container
+	FreeNode(*T)
+}
+
+// Codec of values used by serialization of containers, it allows values to
+// be stored in any format (e.g. CBOR, msgpack or protobuf).
+type Codec[V any] interface {
+	Encode(V) ([]byte, error)
+	Decode([]byte) (V, error)
+}
+
+// SetTrait is behavior shared by ordered sets. Mutations report whether
+// the set is changed, Has reports whether key exists.
+type SetTrait[K any] interface {
+	Length() int
+	Add(K) bool
+	Has(K) bool
+	Cut(K) bool
+}
+
+// MapTrait is behavior shared by ordered maps. Put reports whether pair
+// is new, Get and Cut return the value and whether key exists.
+type MapTrait[K any, V any] interface {
+	Length() int
+	Put(K, V) bool
+	Get(K) (V, bool)
+	Cut(K) (V, bool)
+}
+
+var (
+	_ SetTrait[int] = (*Set[int])(nil)
+	_ SetTrait[int] = (*SubSet[int])(nil)
+	_ SetTrait[int] = (*SyncSet[int])(nil)
+
+	_ MapTrait[int, int] = (*Map[int, int])(nil)
+	_ MapTrait[int, int] = (*HashMap[int, int])(nil)
+	_ MapTrait[int, int] = (*SubMap[int, int])(nil)
+	_ MapTrait[int, int] = (*SyncMap[int, int])(nil)
+	_ MapTrait[int, int] = (*SyncHashMap[int, int])(nil)
+	_ MapTrait[int, int] = (*ShardedMap[int, int])(nil)
+	_ MapTrait[int, int] = (*ConcurrentMap[int, int])(nil)
+	_ MapTrait[int, int] = (*UnrolledMap[int, int])(nil)
+	_ MapTrait[int, int] = (*TombstoneMap[int, int])(nil)
+	_ MapTrait[int, int] = (*IndexedMap[int, int, int])(nil)
+)
+
+// Evict defines the end of capacity-bounded map where pairs are evicted
+type Evict int
+
+const (
+	// EvictMin evicts the first pair of the map
+	EvictMin Evict = iota
+	// EvictMax evicts the last pair of the map
+	EvictMax
+)
+
+// capacity of the map and eviction policy
+type bound[K any, V any] struct {
+	capacity int
+	end      Evict
+	onEvict  func(K, V)
+}
+
+// hash of built-in keys, FNV-1a of binary format mixed by splitmix64
+// finalizer. The hash is stable across processes.
+func hashOf[K Key](key K) uint64 {
+	b, _ := marshalBinary(key)
+
+	h := fnv.New64a()
+	h.Write(b)
+	x := h.Sum64()
+
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// generator of geometric levels, each level takes 1/2ⁿ of nodes below.
+// The level is 1 + number of trailing zero bits of xorshift64 generator
+// divided by n.
+type geometric struct {
+	x uint64
+	n int
+}
+
+func newGeometric(n int, seed uint64) *geometric {
+	if n < 1 {
+		n = 1
+	}
+	if seed == 0 {
+		seed = 0x9e3779b97f4a7c15
+	}
+
+	return &geometric{x: seed, n: n}
+}
+
+func (g *geometric) level(maxL int) int {
+	g.x ^= g.x << 13
+	g.x ^= g.x >> 7
+	g.x ^= g.x << 17
+
+	return min(1+bits.TrailingZeros64(g.x)/g.n, maxL)
+}
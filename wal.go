@@ -0,0 +1,179 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// WAL is durable map, every write is appended to the log before it is
+// applied to the map. The map is rebuilt after crash with ReplayWAL.
+// Writes are not synced, the writer is responsible for durability
+// (e.g. os.File with periodic Sync).
+//
+// Log consists of records, each record is protected by checksum:
+//
+//	uvarint(len) op key [value] crc32
+type WAL[K any, V any] struct {
+	kv    *Map[K, V]
+	w     io.Writer
+	buf   []byte
+	frame []byte
+}
+
+const (
+	walPut = 'P'
+	walCut = 'C'
+
+	// longer records are treated as corrupted log
+	maxRecordSize = maxFieldSize
+)
+
+// ErrCorruptedWAL is returned by replay if the log contains corrupted record
+var ErrCorruptedWAL = errors.New("skiplist: corrupted write-ahead log")
+
+// NewWAL creates durable map that appends writes to the log
+func NewWAL[K any, V any](kv *Map[K, V], w io.Writer) *WAL[K, V] {
+	return &WAL[K, V]{kv: kv, w: w}
+}
+
+// Map returns the underlying map, it must be used for reads only
+func (wal *WAL[K, V]) Map() *Map[K, V] {
+	return wal.kv
+}
+
+// Put key-value pair, returns true if pair is new
+func (wal *WAL[K, V]) Put(key K, val V) (bool, error) {
+	rec, err := appendBinaryField(append(wal.buf[:0], walPut), key)
+	if err != nil {
+		return false, err
+	}
+
+	if rec, err = wal.kv.appendValue(rec, val); err != nil {
+		return false, err
+	}
+
+	if err := wal.append(rec); err != nil {
+		return false, err
+	}
+
+	return wal.kv.Put(key, val), nil
+}
+
+// Cut key from the map, returns true if key is removed
+func (wal *WAL[K, V]) Cut(key K) (bool, error) {
+	rec, err := appendBinaryField(append(wal.buf[:0], walCut), key)
+	if err != nil {
+		return false, err
+	}
+
+	if err := wal.append(rec); err != nil {
+		return false, err
+	}
+
+	_, ok := wal.kv.Cut(key)
+	return ok, nil
+}
+
+func (wal *WAL[K, V]) append(rec []byte) error {
+	wal.buf = rec
+
+	wal.frame = binary.AppendUvarint(wal.frame[:0], uint64(len(rec)))
+	wal.frame = append(wal.frame, rec...)
+	wal.frame = binary.BigEndian.AppendUint32(wal.frame, crc32.ChecksumIEEE(rec))
+
+	_, err := wal.w.Write(wal.frame)
+	return err
+}
+
+// ReplayWAL applies writes of the log to the map, returns number of applied
+// records and offset of the end of the last valid record. The record torn by
+// crash at the end of log is ignored, the log must be truncated at the offset
+// before new writes are appended, otherwise the next replay fails at torn bytes.
+//
+//	n, offset, err := skiplist.ReplayWAL(kv, f)
+//	f.Truncate(offset)
+//	f.Seek(offset, io.SeekStart)
+func ReplayWAL[K any, V any](kv *Map[K, V], r io.Reader) (int, int64, error) {
+	cr := newCountReader(r)
+
+	var buf []byte
+	for n := 0; ; n++ {
+		offset := cr.n
+
+		size, err := binary.ReadUvarint(cr)
+		switch {
+		case err == io.EOF:
+			return n, offset, nil
+		case err != nil:
+			return n, offset, torn(err)
+		}
+
+		if size > maxRecordSize {
+			return n, offset, ErrCorruptedWAL
+		}
+
+		if buf, err = readBytes(cr, buf, int(size)+4); err != nil {
+			return n, offset, torn(err)
+		}
+
+		rec := buf[:size]
+		if crc32.ChecksumIEEE(rec) != binary.BigEndian.Uint32(buf[size:]) {
+			return n, offset, ErrCorruptedWAL
+		}
+
+		if err := replay(kv, rec); err != nil {
+			return n, offset, err
+		}
+	}
+}
+
+// torn record at the end of log is not an error
+func torn(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil
+	}
+	return err
+}
+
+func replay[K any, V any](kv *Map[K, V], rec []byte) error {
+	if len(rec) == 0 {
+		return ErrCorruptedWAL
+	}
+
+	br := bytes.NewReader(rec[1:])
+	r := newCountReader(br)
+
+	var key K
+	if _, err := readBinaryField(r, nil, &key); err != nil {
+		return ErrCorruptedWAL
+	}
+
+	switch rec[0] {
+	case walPut:
+		var val V
+		if _, err := kv.readValue(r, nil, &val); err != nil || br.Len() != 0 {
+			return ErrCorruptedWAL
+		}
+		kv.Put(key, val)
+	case walCut:
+		if br.Len() != 0 {
+			return ErrCorruptedWAL
+		}
+		kv.Cut(key)
+	default:
+		return ErrCorruptedWAL
+	}
+
+	return nil
+}
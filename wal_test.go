@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2022 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/skiplist
+//
+
+package skiplist_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/fogfish/skiplist"
+)
+
+func TestWAL(t *testing.T) {
+	var log bytes.Buffer
+
+	wal := skiplist.NewWAL(skiplist.NewMap[int, string](), &log)
+	for i := 0; i < 100; i++ {
+		isNew, err := wal.Put(i, "x")
+		it.Then(t).Should(it.Nil(err), it.True(isNew))
+	}
+	for i := 0; i < 100; i += 2 {
+		ok, err := wal.Cut(i)
+		it.Then(t).Should(it.Nil(err), it.True(ok))
+	}
+	wal.Put(1, "y")
+
+	eq := func(a, b string) bool { return a == b }
+
+	t.Run("Replay", func(t *testing.T) {
+		kv := skiplist.NewMap[int, string]()
+		n, offset, err := skiplist.ReplayWAL(kv, bytes.NewReader(log.Bytes()))
+		v, _ := kv.Get(1)
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(n, 151),
+			it.Equal(offset, int64(log.Len())),
+			it.Equal(kv.Length(), 50),
+			it.Equal(v, "y"),
+			it.True(skiplist.Equal(kv, wal.Map(), eq)),
+		)
+	})
+
+	t.Run("Torn", func(t *testing.T) {
+		kv := skiplist.NewMap[int, string]()
+		b := bytes.Clone(log.Bytes()[:log.Len()-2])
+		n, offset, err := skiplist.ReplayWAL(kv, bytes.NewReader(b))
+
+		v, _ := kv.Get(1)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(n, 150),
+			it.Equal(v, "x"),
+			it.Less(offset, int64(len(b))),
+		)
+
+		// log is truncated at offset before appending
+		w := bytes.NewBuffer(b[:offset])
+		skiplist.NewWAL(kv, w).Put(2, "z")
+
+		kv = skiplist.NewMap[int, string]()
+		n, _, err = skiplist.ReplayWAL(kv, bytes.NewReader(w.Bytes()))
+
+		v, _ = kv.Get(2)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(n, 151),
+			it.Equal(v, "z"),
+		)
+	})
+
+	t.Run("Corrupted", func(t *testing.T) {
+		b := bytes.Clone(log.Bytes())
+		b[5] ^= 0xff
+
+		_, _, err := skiplist.ReplayWAL(skiplist.NewMap[int, string](), bytes.NewReader(b))
+		it.Then(t).Should(
+			it.Equal(err, skiplist.ErrCorruptedWAL),
+		)
+	})
+
+	t.Run("Trailing", func(t *testing.T) {
+		var one bytes.Buffer
+		skiplist.NewWAL(skiplist.NewMap[int, string](), &one).Cut(1)
+
+		size, k := binary.Uvarint(one.Bytes())
+		rec := append(bytes.Clone(one.Bytes()[k:k+int(size)]), 0)
+
+		b := binary.AppendUvarint(nil, uint64(len(rec)))
+		b = append(b, rec...)
+		b = binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(rec))
+
+		n, offset, err := skiplist.ReplayWAL(skiplist.NewMap[int, string](), bytes.NewReader(b))
+		it.Then(t).Should(
+			it.Equal(n, 0),
+			it.Equal(offset, int64(0)),
+			it.Equal(err, skiplist.ErrCorruptedWAL),
+		)
+	})
+
+	t.Run("Oversized", func(t *testing.T) {
+		b := append(bytes.Clone(log.Bytes()), binary.AppendUvarint(nil, 1<<64-1)...)
+
+		kv := skiplist.NewMap[int, string]()
+		n, offset, err := skiplist.ReplayWAL(kv, bytes.NewReader(b))
+		it.Then(t).Should(
+			it.Equal(n, 151),
+			it.Equal(offset, int64(log.Len())),
+			it.Equal(err, skiplist.ErrCorruptedWAL),
+		)
+	})
+}